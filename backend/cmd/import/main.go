@@ -0,0 +1,121 @@
+// Command import loads a CSV/TSV vocabulary file into a user's study items,
+// using the same parsing, deduplication and mastery seeding as the
+// POST /v1/imports endpoint but running to completion in the foreground.
+//
+//	go run ./cmd/import -email user@example.com -file deck.tsv -map word=Front,meaning=Back
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"lexipath-backend/internal/config"
+	"lexipath-backend/internal/repositories"
+	"lexipath-backend/internal/services"
+
+	"go.uber.org/zap"
+)
+
+func main() {
+	email := flag.String("email", "", "email of the user to import into (required)")
+	path := flag.String("file", "", "CSV or TSV file to import (required)")
+	format := flag.String("format", "", "csv or tsv; inferred from the file extension when empty")
+	header := flag.Bool("header", true, "the first line names the columns")
+	mapping := flag.String("map", "", `column mapping, e.g. "word=Front,meaning=Back,tags=3"`)
+	seedMastery := flag.Int("mastery", -1, "mastery score 0-100 for rows without a mastery column")
+	flag.Parse()
+
+	if *email == "" || *path == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatal("Failed to initialize logger:", err)
+	}
+	defer logger.Sync()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	db, err := repositories.NewPostgresDB(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	user, err := repositories.NewUserRepository(db).GetByEmail(ctx, *email)
+	if err != nil {
+		log.Fatalf("Failed to look up user: %v", err)
+	}
+	if user == nil {
+		log.Fatalf("No user with email %s", *email)
+	}
+
+	profile, err := repositories.NewProfileRepository(db).GetByUserID(ctx, user.ID)
+	if err != nil {
+		log.Fatalf("Failed to get profile: %v", err)
+	}
+	if profile == nil {
+		log.Fatalf("User %s has no profile yet", *email)
+	}
+
+	data, err := os.ReadFile(*path)
+	if err != nil {
+		log.Fatalf("Failed to read file: %v", err)
+	}
+
+	opts := services.ImportOptions{
+		Format:    services.ImportFormat(strings.ToLower(*format)),
+		HasHeader: *header,
+	}
+	if opts.Format == "" {
+		opts.Format = services.ImportFormatCSV
+		if ext := strings.ToLower(filepath.Ext(*path)); ext == ".tsv" || ext == ".tab" || ext == ".txt" {
+			opts.Format = services.ImportFormatTSV
+		}
+	}
+	if opts.Mapping, err = services.ParseImportMapping(*mapping); err != nil {
+		log.Fatal(err)
+	}
+	if *seedMastery >= 0 {
+		opts.SeedMastery = seedMastery
+	}
+
+	importService := services.NewImportService(
+		repositories.NewImportRepository(db),
+		repositories.NewContentRepository(db),
+		repositories.NewLemmaRepository(db),
+		repositories.NewMasteryRepository(db),
+		services.NewPromptGuard(logger),
+		logger,
+	)
+
+	job, err := importService.RunImport(ctx, profile, filepath.Base(*path), data, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Import %s: %d rows, %d imported, %d duplicates skipped, %d failed\n",
+		job.ID, job.TotalRows, job.Imported, job.Skipped, job.Failed)
+	for _, rowErr := range job.Errors {
+		fmt.Printf("  line %d: %s\n", rowErr.Line, rowErr.Error)
+	}
+	if job.Failed > len(job.Errors) {
+		fmt.Printf("  ... and %d more\n", job.Failed-len(job.Errors))
+	}
+
+	if job.Failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
@@ -13,9 +15,59 @@ type Config struct {
 	GeminiAPIKey      string
 	FirebaseProjectID string
 	Timezone          string
+	AdminEmails       []string
+	Gemini            GeminiConfig
+	Pregeneration     PregenerationConfig
+	Grading           GradingConfig
+	Quiz              QuizConfig
+}
+
+// QuizConfig controls how quiz questions are made.
+type QuizConfig struct {
+	// Generator is local or llm: whether multiple choice, fill-blank and
+	// sentence questions are built from the user's content or by the LLM.
+	// Situation questions always use the LLM.
+	Generator string
+}
+
+// GradingConfig controls how strictly typed quiz answers are graded.
+type GradingConfig struct {
+	// AccentMode is strict, lenient or ignore: whether an answer that only
+	// misses accents is wrong, close or exact.
+	AccentMode string
+}
+
+// PregenerationConfig controls the worker that generates tomorrow's content
+// ahead of each user's local midnight.
+type PregenerationConfig struct {
+	Enabled      bool
+	Interval     time.Duration
+	LeadTime     time.Duration
+	ActiveWindow time.Duration
+	Concurrency  int
+	MaxPerMinute int
+}
+
+// GeminiConfig holds the model and generation parameters used for each
+// LLM-backed task, so cost and quality can be tuned per feature.
+type GeminiConfig struct {
+	SafetyThreshold string
+	DailyContent    GenerationSettings
+	Quiz            GenerationSettings
+	Translate       GenerationSettings
+	Grading         GenerationSettings
+}
+
+type GenerationSettings struct {
+	Model           string
+	Temperature     float64
+	TopP            float64
+	MaxOutputTokens int
 }
 
 func Load() (*Config, error) {
+	defaultModel := getEnv("GEMINI_MODEL", "gemini-2.0-flash")
+
 	cfg := &Config{
 		Port:              getEnv("PORT", "8080"),
 		Environment:       getEnv("ENVIRONMENT", "development"),
@@ -24,11 +76,44 @@ func Load() (*Config, error) {
 		GeminiAPIKey:      getEnv("GEMINI_API_KEY", ""),
 		FirebaseProjectID: getEnv("FIREBASE_PROJECT_ID", ""),
 		Timezone:          getEnv("TIMEZONE", "Asia/Kolkata"),
+		AdminEmails:       getEnvAsSlice("ADMIN_EMAILS"),
+		Gemini: GeminiConfig{
+			SafetyThreshold: getEnv("GEMINI_SAFETY_THRESHOLD", "BLOCK_MEDIUM_AND_ABOVE"),
+			DailyContent:    loadGenerationSettings("GEMINI_DAILY_CONTENT", defaultModel, 0.9, 4096),
+			Quiz:            loadGenerationSettings("GEMINI_QUIZ", defaultModel, 0.7, 512),
+			Translate:       loadGenerationSettings("GEMINI_TRANSLATE", defaultModel, 0.2, 512),
+			Grading:         loadGenerationSettings("GEMINI_GRADING", defaultModel, 0.0, 512),
+		},
+		Pregeneration: PregenerationConfig{
+			Enabled:      getEnvAsBool("PREGENERATION_ENABLED", true),
+			Interval:     time.Duration(getEnvAsInt("PREGENERATION_INTERVAL_MINUTES", 15)) * time.Minute,
+			LeadTime:     time.Duration(getEnvAsInt("PREGENERATION_LEAD_HOURS", 3)) * time.Hour,
+			ActiveWindow: time.Duration(getEnvAsInt("PREGENERATION_ACTIVE_DAYS", 7)) * 24 * time.Hour,
+			Concurrency:  getEnvAsInt("PREGENERATION_CONCURRENCY", 4),
+			MaxPerMinute: getEnvAsInt("PREGENERATION_MAX_PER_MINUTE", 30),
+		},
+		Grading: GradingConfig{
+			AccentMode: strings.ToLower(getEnv("GRADING_ACCENT_MODE", "lenient")),
+		},
+		Quiz: QuizConfig{
+			Generator: strings.ToLower(getEnv("QUIZ_GENERATOR", "local")),
+		},
 	}
 
 	return cfg, nil
 }
 
+// loadGenerationSettings reads <prefix>_MODEL, <prefix>_TEMPERATURE,
+// <prefix>_TOP_P and <prefix>_MAX_OUTPUT_TOKENS, falling back to the given defaults.
+func loadGenerationSettings(prefix, defaultModel string, defaultTemperature float64, defaultMaxOutputTokens int) GenerationSettings {
+	return GenerationSettings{
+		Model:           getEnv(prefix+"_MODEL", defaultModel),
+		Temperature:     getEnvAsFloat(prefix+"_TEMPERATURE", defaultTemperature),
+		TopP:            getEnvAsFloat(prefix+"_TOP_P", 0.95),
+		MaxOutputTokens: getEnvAsInt(prefix+"_MAX_OUTPUT_TOKENS", defaultMaxOutputTokens),
+	}
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -45,6 +130,25 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsSlice(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
@@ -1,8 +1,13 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"lexipath-backend/internal/models"
@@ -20,6 +25,11 @@ type Handlers struct {
 	contentService    *services.ContentService
 	quizService       *services.QuizService
 	weeklyPlanService *services.WeeklyPlanService
+	usageService      *services.UsageService
+	lexiconService    *services.LexiconService
+	importService     *services.ImportService
+	exportService     *services.ExportService
+	reviewService     *services.ReviewSessionService
 	logger            *zap.Logger
 }
 
@@ -29,6 +39,11 @@ func New(
 	contentService *services.ContentService,
 	quizService *services.QuizService,
 	weeklyPlanService *services.WeeklyPlanService,
+	usageService *services.UsageService,
+	lexiconService *services.LexiconService,
+	importService *services.ImportService,
+	exportService *services.ExportService,
+	reviewService *services.ReviewSessionService,
 	logger *zap.Logger,
 ) *Handlers {
 	return &Handlers{
@@ -37,6 +52,11 @@ func New(
 		contentService:    contentService,
 		quizService:       quizService,
 		weeklyPlanService: weeklyPlanService,
+		usageService:      usageService,
+		lexiconService:    lexiconService,
+		importService:     importService,
+		exportService:     exportService,
+		reviewService:     reviewService,
 		logger:            logger,
 	}
 }
@@ -94,6 +114,10 @@ func (h *Handlers) GetDailyContent(c *gin.Context) {
 	}
 
 	content, err := h.contentService.GetDailyContent(c.Request.Context(), user.ID, profile, req.Date)
+	if errors.Is(err, services.ErrUsageBudgetExceeded) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Daily usage limit reached. Please try again later."})
+		return
+	}
 	if err != nil {
 		h.logger.Error("Failed to get daily content", zap.Error(err), zap.String("user_id", user.ID.String()))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get daily content"})
@@ -103,6 +127,92 @@ func (h *Handlers) GetDailyContent(c *gin.Context) {
 	c.JSON(http.StatusOK, content)
 }
 
+func (h *Handlers) SkipDailyContent(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	var req models.SkipDailyContentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := h.profileService.GetProfile(c.Request.Context(), user.ID)
+	if err != nil {
+		h.logger.Error("Failed to get profile", zap.Error(err), zap.String("user_id", user.ID.String()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get profile"})
+		return
+	}
+
+	if profile == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Profile not found. Please complete onboarding first."})
+		return
+	}
+
+	set, err := h.contentService.SkipDailyContent(c.Request.Context(), profile, &req)
+	if errors.Is(err, services.ErrContentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Content not found"})
+		return
+	}
+	if errors.Is(err, services.ErrInvalidSkip) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrSkipLimitReached) || errors.Is(err, services.ErrUsageBudgetExceeded) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Daily limit reached. Please try again later."})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to skip daily content", zap.Error(err), zap.String("user_id", user.ID.String()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to skip daily content"})
+		return
+	}
+
+	c.JSON(http.StatusOK, set)
+}
+
+func (h *Handlers) AddWord(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	var req models.AddWordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := h.profileService.GetProfile(c.Request.Context(), user.ID)
+	if err != nil {
+		h.logger.Error("Failed to get profile", zap.Error(err), zap.String("user_id", user.ID.String()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get profile"})
+		return
+	}
+
+	if profile == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Profile not found. Please complete onboarding first."})
+		return
+	}
+
+	content, created, err := h.contentService.AddCustomWord(c.Request.Context(), profile, &req)
+	if errors.Is(err, services.ErrUsageBudgetExceeded) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Daily usage limit reached. Please try again later."})
+		return
+	}
+	if errors.Is(err, services.ErrInputRejected) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to add word", zap.Error(err), zap.String("user_id", user.ID.String()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add word"})
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, content)
+}
+
 func (h *Handlers) SubmitQuiz(c *gin.Context) {
 	user := c.MustGet("user").(*models.User)
 
@@ -112,22 +222,203 @@ func (h *Handlers) SubmitQuiz(c *gin.Context) {
 		return
 	}
 
-	// For now, we'll generate the quiz question on-the-fly
-	// In a production system, you might want to store quiz questions
-	// when content is generated to ensure consistency
+	profile, err := h.profileService.GetProfile(c.Request.Context(), user.ID)
+	if err != nil {
+		h.logger.Error("Failed to get profile", zap.Error(err), zap.String("user_id", user.ID.String()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get profile"})
+		return
+	}
+
+	if profile == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Profile not found. Please complete onboarding first."})
+		return
+	}
 
-	// This is a simplified implementation - you would typically
-	// retrieve the stored quiz question and correct answer
-	quizLog, err := h.quizService.SubmitQuiz(c.Request.Context(), user.ID, &req, "placeholder_correct_answer", "placeholder_question", []string{})
+	quizLog, err := h.reviewService.SubmitQuiz(c.Request.Context(), profile, &req)
 	if err != nil {
-		h.logger.Error("Failed to submit quiz", zap.Error(err), zap.String("user_id", user.ID.String()))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit quiz"})
+		h.reviewSessionError(c, user, err, "Failed to submit quiz")
 		return
 	}
 
 	c.JSON(http.StatusOK, quizLog)
 }
 
+func (h *Handlers) StartReviewSession(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	var req models.StartReviewSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := h.profileService.GetProfile(c.Request.Context(), user.ID)
+	if err != nil {
+		h.logger.Error("Failed to get profile", zap.Error(err), zap.String("user_id", user.ID.String()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get profile"})
+		return
+	}
+
+	if profile == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Profile not found. Please complete onboarding first."})
+		return
+	}
+
+	session, err := h.reviewService.StartSession(c.Request.Context(), profile, &req)
+	if err != nil {
+		h.reviewSessionError(c, user, err, "Failed to start review session")
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+func (h *Handlers) GetActiveReviewSession(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	session, err := h.reviewService.GetActiveSession(c.Request.Context(), user.ID)
+	if err != nil {
+		h.reviewSessionError(c, user, err, "Failed to get review session")
+		return
+	}
+
+	if session == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No review session in progress"})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+func (h *Handlers) GetReviewSession(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session id"})
+		return
+	}
+
+	session, err := h.reviewService.GetSession(c.Request.Context(), user.ID, sessionID)
+	if err != nil {
+		h.reviewSessionError(c, user, err, "Failed to get review session")
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+func (h *Handlers) GetReviewQuestion(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session id"})
+		return
+	}
+
+	question, err := h.reviewService.GetQuestion(c.Request.Context(), user.ID, sessionID)
+	if err != nil {
+		h.reviewSessionError(c, user, err, "Failed to get review question")
+		return
+	}
+
+	c.JSON(http.StatusOK, question)
+}
+
+func (h *Handlers) SubmitReviewAnswer(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session id"})
+		return
+	}
+
+	var req models.SubmitReviewAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := h.profileService.GetProfile(c.Request.Context(), user.ID)
+	if err != nil {
+		h.logger.Error("Failed to get profile", zap.Error(err), zap.String("user_id", user.ID.String()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get profile"})
+		return
+	}
+
+	if profile == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Profile not found. Please complete onboarding first."})
+		return
+	}
+
+	result, err := h.reviewService.SubmitAnswer(c.Request.Context(), profile, sessionID, req.Answer)
+	if err != nil {
+		h.reviewSessionError(c, user, err, "Failed to submit review answer")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *Handlers) FinishReviewSession(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	sessionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session id"})
+		return
+	}
+
+	session, err := h.reviewService.FinishSession(c.Request.Context(), user.ID, sessionID)
+	if err != nil {
+		h.reviewSessionError(c, user, err, "Failed to finish review session")
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// reviewSessionError responds to an error from the review session service.
+func (h *Handlers) reviewSessionError(c *gin.Context, user *models.User, err error, message string) {
+	if errors.Is(err, services.ErrInvalidReviewSession) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrNothingToReview) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No words to review"})
+		return
+	}
+	if errors.Is(err, services.ErrReviewSessionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Review session not found"})
+		return
+	}
+	if errors.Is(err, services.ErrContentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Content not found"})
+		return
+	}
+	if errors.Is(err, services.ErrQuizQuestionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No question was asked for this word and quiz type"})
+		return
+	}
+	if errors.Is(err, services.ErrReviewSessionConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrInputRejected) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrUsageBudgetExceeded) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Daily usage limit reached. Please try again later."})
+		return
+	}
+
+	h.logger.Error(message, zap.Error(err), zap.String("user_id", user.ID.String()))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
+
 func (h *Handlers) GenerateWeeklyPlan(c *gin.Context) {
 	user := c.MustGet("user").(*models.User)
 
@@ -164,6 +455,14 @@ func (h *Handlers) Translate(c *gin.Context) {
 	}
 
 	translation, err := h.contentService.Translate(c.Request.Context(), user.ID, &req)
+	if errors.Is(err, services.ErrUsageBudgetExceeded) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Daily usage limit reached. Please try again later."})
+		return
+	}
+	if errors.Is(err, services.ErrInputRejected) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		h.logger.Error("Failed to translate", zap.Error(err), zap.String("user_id", user.ID.String()))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -173,32 +472,592 @@ func (h *Handlers) Translate(c *gin.Context) {
 	c.JSON(http.StatusOK, translation)
 }
 
-func (h *Handlers) GetContentHistory(c *gin.Context) {
+func (h *Handlers) ListTranslations(c *gin.Context) {
 	user := c.MustGet("user").(*models.User)
 
-	limitStr := c.DefaultQuery("limit", "20")
-	offsetStr := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	if err != nil || limit <= 0 || limit > 100 {
 		limit = 20
 	}
 
-	offset, err := strconv.Atoi(offsetStr)
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
 	if err != nil || offset < 0 {
 		offset = 0
 	}
 
-	history, err := h.contentService.GetContentHistory(c.Request.Context(), user.ID, limit, offset)
+	translations, err := h.contentService.ListTranslations(c.Request.Context(), user.ID, limit, offset)
 	if err != nil {
-		h.logger.Error("Failed to get content history", zap.Error(err), zap.String("user_id", user.ID.String()))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get content history"})
+		h.logger.Error("Failed to list translations", zap.Error(err), zap.String("user_id", user.ID.String()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list translations"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"content": history,
-		"limit":   limit,
-		"offset":  offset,
+		"translations": translations,
+		"limit":        limit,
+		"offset":       offset,
 	})
 }
+
+func (h *Handlers) PromoteTranslation(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	translationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid translation id"})
+		return
+	}
+
+	profile, err := h.profileService.GetProfile(c.Request.Context(), user.ID)
+	if err != nil {
+		h.logger.Error("Failed to get profile", zap.Error(err), zap.String("user_id", user.ID.String()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get profile"})
+		return
+	}
+
+	if profile == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Profile not found. Please complete onboarding first."})
+		return
+	}
+
+	content, err := h.contentService.PromoteTranslation(c.Request.Context(), profile, translationID)
+	if errors.Is(err, services.ErrInvalidPromotion) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to promote translation", zap.Error(err), zap.String("user_id", user.ID.String()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to promote translation"})
+		return
+	}
+
+	if content == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Translation not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, content)
+}
+
+// CreateImport accepts a CSV/TSV upload in the "file" form field and starts
+// importing it in the background. Optional form fields: format (csv or tsv,
+// inferred from the file name otherwise), header (defaults to true), mapping
+// (e.g. "word=Front,meaning=Back") and seed_mastery (0 to 100).
+func (h *Handlers) CreateImport(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if fileHeader.Size > services.MaxImportFileSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "File is too large"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, services.MaxImportFileSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+
+	opts := services.ImportOptions{
+		Format:    services.ImportFormat(strings.ToLower(c.PostForm("format"))),
+		HasHeader: c.DefaultPostForm("header", "true") == "true",
+	}
+	if opts.Format == "" {
+		opts.Format = services.ImportFormatCSV
+		if ext := strings.ToLower(filepath.Ext(fileHeader.Filename)); ext == ".tsv" || ext == ".tab" || ext == ".txt" {
+			opts.Format = services.ImportFormatTSV
+		}
+	}
+	if opts.Mapping, err = services.ParseImportMapping(c.PostForm("mapping")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if value := c.PostForm("seed_mastery"); value != "" {
+		seed, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "seed_mastery must be a number"})
+			return
+		}
+		opts.SeedMastery = &seed
+	}
+
+	profile, err := h.profileService.GetProfile(c.Request.Context(), user.ID)
+	if err != nil {
+		h.logger.Error("Failed to get profile", zap.Error(err), zap.String("user_id", user.ID.String()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get profile"})
+		return
+	}
+
+	if profile == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Profile not found. Please complete onboarding first."})
+		return
+	}
+
+	job, err := h.importService.StartImport(c.Request.Context(), profile, fileHeader.Filename, data, opts)
+	if errors.Is(err, services.ErrInvalidImport) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to start import", zap.Error(err), zap.String("user_id", user.ID.String()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start import"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+func (h *Handlers) GetImport(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	jobID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid import id"})
+		return
+	}
+
+	job, err := h.importService.GetJob(c.Request.Context(), user.ID, jobID)
+	if err != nil {
+		h.logger.Error("Failed to get import", zap.Error(err), zap.String("user_id", user.ID.String()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get import"})
+		return
+	}
+
+	if job == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Import not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+func (h *Handlers) ExportVocabulary(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	format, err := services.ParseExportFormat(c.DefaultQuery("format", "csv"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv, anki or json"})
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", `attachment; filename="`+format.Filename()+`"`)
+
+	err = h.exportService.ExportVocabulary(c.Request.Context(), user.ID, format, c.Writer)
+	if err != nil {
+		h.logger.Error("Failed to export vocabulary", zap.Error(err), zap.String("user_id", user.ID.String()))
+		// Once streaming has started the status is sent, so the download is
+		// just cut short.
+		if !c.Writer.Written() {
+			c.Header("Content-Disposition", "")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export vocabulary"})
+		}
+		return
+	}
+}
+
+func (h *Handlers) ListContent(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	var filter models.ContentFilter
+	if filter.From, err = queryDate(c, "from"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.To, err = queryDate(c, "to"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if bucket := models.MasteryBucket(c.Query("mastery")); bucket != "" {
+		switch bucket {
+		case models.MasteryBucketNew, models.MasteryBucketWeak, models.MasteryBucketModerate, models.MasteryBucketStrong:
+			filter.MasteryBucket = bucket
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "mastery must be new, weak, moderate or strong"})
+			return
+		}
+	}
+
+	if filter.MinAccuracy, err = queryFraction(c, "min_accuracy"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.MaxAccuracy, err = queryFraction(c, "max_accuracy"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter.Tags = c.QueryArray("tag")
+	filter.Bookmarked = c.Query("bookmarked") == "true"
+
+	content, nextCursor, err := h.contentService.ListContent(c.Request.Context(), user.ID, filter, c.Query("cursor"), limit)
+	if errors.Is(err, services.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+		return
+	}
+	if errors.Is(err, services.ErrInvalidAnnotation) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to list content", zap.Error(err), zap.String("user_id", user.ID.String()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list content"})
+		return
+	}
+
+	if content == nil {
+		content = []*models.ContentProgress{}
+	}
+
+	response := gin.H{
+		"content":     content,
+		"limit":       limit,
+		"next_cursor": nil,
+	}
+	if nextCursor != "" {
+		response["next_cursor"] = nextCursor
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handlers) SearchContent(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 50 {
+		limit = 20
+	}
+
+	profile, err := h.profileService.GetProfile(c.Request.Context(), user.ID)
+	if err != nil {
+		h.logger.Error("Failed to get profile", zap.Error(err), zap.String("user_id", user.ID.String()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get profile"})
+		return
+	}
+
+	if profile == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Profile not found. Please complete onboarding first."})
+		return
+	}
+
+	results, err := h.contentService.SearchContent(c.Request.Context(), profile, c.Query("q"), limit)
+	if errors.Is(err, services.ErrInvalidSearch) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q must be between 1 and 200 characters"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to search content", zap.Error(err), zap.String("user_id", user.ID.String()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search content"})
+		return
+	}
+
+	if results == nil {
+		results = []*models.ContentSearchResult{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results": results,
+		"query":   c.Query("q"),
+	})
+}
+
+func (h *Handlers) GetContent(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	contentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content id"})
+		return
+	}
+
+	content, err := h.contentService.GetContent(c.Request.Context(), user.ID, contentID)
+	if errors.Is(err, services.ErrContentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Content not found"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to get content", zap.Error(err), zap.String("user_id", user.ID.String()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get content"})
+		return
+	}
+
+	c.JSON(http.StatusOK, content)
+}
+
+func (h *Handlers) UpdateContent(c *gin.Context) {
+	var req models.UpdateContentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.annotateContent(c, func(userID, contentID uuid.UUID) (*models.DailyContent, error) {
+		return h.contentService.UpdateContent(c.Request.Context(), userID, contentID, &req)
+	})
+}
+
+func (h *Handlers) BookmarkContent(c *gin.Context) {
+	bookmarked := c.Request.Method != http.MethodDelete
+	h.annotateContent(c, func(userID, contentID uuid.UUID) (*models.DailyContent, error) {
+		return h.contentService.UpdateContent(c.Request.Context(), userID, contentID, &models.UpdateContentRequest{Bookmarked: &bookmarked})
+	})
+}
+
+func (h *Handlers) AddContentTags(c *gin.Context) {
+	var req models.AddContentTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.annotateContent(c, func(userID, contentID uuid.UUID) (*models.DailyContent, error) {
+		return h.contentService.AddContentTags(c.Request.Context(), userID, contentID, req.Tags)
+	})
+}
+
+func (h *Handlers) RemoveContentTag(c *gin.Context) {
+	h.annotateContent(c, func(userID, contentID uuid.UUID) (*models.DailyContent, error) {
+		return h.contentService.RemoveContentTag(c.Request.Context(), userID, contentID, c.Param("tag"))
+	})
+}
+
+// annotateContent runs an annotation change on the content item in the path
+// and responds with the updated item.
+func (h *Handlers) annotateContent(c *gin.Context, update func(userID, contentID uuid.UUID) (*models.DailyContent, error)) {
+	user := c.MustGet("user").(*models.User)
+
+	contentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content id"})
+		return
+	}
+
+	content, err := update(user.ID, contentID)
+	if errors.Is(err, services.ErrContentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Content not found"})
+		return
+	}
+	if errors.Is(err, services.ErrInvalidAnnotation) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to update content", zap.Error(err), zap.String("user_id", user.ID.String()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update content"})
+		return
+	}
+
+	c.JSON(http.StatusOK, content)
+}
+
+func (h *Handlers) ListTags(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	tags, err := h.contentService.ListTags(c.Request.Context(), user.ID)
+	if err != nil {
+		h.logger.Error("Failed to list tags", zap.Error(err), zap.String("user_id", user.ID.String()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list tags"})
+		return
+	}
+
+	if tags == nil {
+		tags = []*models.TagCount{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+// ListDueReviews returns the words due for review by the client's date,
+// narrowed to those carrying every tag given, such as ?tag=work.
+func (h *Handlers) ListDueReviews(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	date, err := queryDate(c, "date")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if date == nil {
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		date = &today
+	}
+
+	content, err := h.contentService.ListDueReviews(c.Request.Context(), user.ID, *date, c.QueryArray("tag"), limit)
+	if errors.Is(err, services.ErrInvalidAnnotation) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to list due reviews", zap.Error(err), zap.String("user_id", user.ID.String()))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list due reviews"})
+		return
+	}
+
+	if content == nil {
+		content = []*models.ContentProgress{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"content": content})
+}
+
+// queryDate parses an optional YYYY-MM-DD query parameter.
+func queryDate(c *gin.Context, param string) (*time.Time, error) {
+	value := c.Query(param)
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a date in YYYY-MM-DD format", param)
+	}
+	return &date, nil
+}
+
+// queryFraction parses an optional query parameter between 0 and 1.
+func queryFraction(c *gin.Context, param string) (*float64, error) {
+	value := c.Query(param)
+	if value == "" {
+		return nil, nil
+	}
+	fraction, err := strconv.ParseFloat(value, 64)
+	if err != nil || fraction < 0 || fraction > 1 {
+		return nil, fmt.Errorf("%s must be a number between 0 and 1", param)
+	}
+	return &fraction, nil
+}
+
+func (h *Handlers) GetUsageSummary(c *gin.Context) {
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -30)
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		parsed, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a YYYY-MM-DD date"})
+			return
+		}
+		from = parsed
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		parsed, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a YYYY-MM-DD date"})
+			return
+		}
+		// Include the whole of the last day
+		to = parsed.AddDate(0, 0, 1)
+	}
+
+	var groupBy []string
+	if groupByStr := c.DefaultQuery("group_by", "feature,model"); groupByStr != "" {
+		groupBy = strings.Split(groupByStr, ",")
+	}
+	for _, dimension := range groupBy {
+		if dimension != "user" && dimension != "feature" && dimension != "model" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "group_by accepts user, feature and model"})
+			return
+		}
+	}
+
+	summary, err := h.usageService.GetSummary(c.Request.Context(), from, to, groupBy)
+	if err != nil {
+		h.logger.Error("Failed to get usage summary", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get usage summary"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":     from,
+		"to":       to,
+		"group_by": groupBy,
+		"usage":    summary,
+	})
+}
+
+func (h *Handlers) SetUsageBudget(c *gin.Context) {
+	var req models.SetUsageBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if (req.Tier == nil) == (req.UserID == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Exactly one of tier or user_id is required"})
+		return
+	}
+
+	budget, err := h.usageService.SetBudget(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("Failed to set usage budget", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set usage budget"})
+		return
+	}
+
+	c.JSON(http.StatusOK, budget)
+}
+
+func (h *Handlers) CreateLexiconEntries(c *gin.Context) {
+	var req models.CreateLexiconEntriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entries, err := h.lexiconService.AddCurated(c.Request.Context(), req.Entries)
+	if errors.Is(err, services.ErrInvalidLexiconEntry) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to add lexicon entries", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add lexicon entries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
+
+func (h *Handlers) DisableLexiconEntry(c *gin.Context) {
+	entryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lexicon entry id"})
+		return
+	}
+
+	found, err := h.lexiconService.SetActive(c.Request.Context(), entryID, false)
+	if err != nil {
+		h.logger.Error("Failed to disable lexicon entry", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to disable lexicon entry"})
+		return
+	}
+
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Lexicon entry not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
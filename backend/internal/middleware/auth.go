@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strings"
 
+	"lexipath-backend/internal/models"
 	"lexipath-backend/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -40,3 +41,23 @@ func AuthRequired(authService *services.AuthService) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// AdminRequired must run after AuthRequired. It only lets through users whose
+// verified email is in the configured admin list.
+func AdminRequired(adminEmails []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := c.MustGet("user").(*models.User)
+
+		// Anyone can sign up with an admin's address without verifying it,
+		// so only the verified email counts
+		for _, email := range adminEmails {
+			if strings.EqualFold(email, user.VerifiedEmail) {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		c.Abort()
+	}
+}
@@ -10,8 +10,12 @@ type User struct {
 	ID          uuid.UUID `json:"id" db:"id"`
 	FirebaseUID string    `json:"firebase_uid" db:"firebase_uid"`
 	Email       string    `json:"email" db:"email"`
+	Tier        string    `json:"tier" db:"tier"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	// VerifiedEmail is the email in the ID token if Firebase has verified
+	// it, and empty otherwise. It isn't stored.
+	VerifiedEmail string `json:"-" db:"-"`
 }
 
 type GoalType string
@@ -37,29 +41,196 @@ type Profile struct {
 	BaseLang     *string       `json:"base_lang,omitempty" db:"base_lang"`
 	Level        LanguageLevel `json:"level" db:"level"`
 	IndustrySector *string     `json:"industry_sector,omitempty" db:"industry_sector"`
+	Timezone     *string       `json:"timezone,omitempty" db:"timezone"`
+	DailyWordCount int         `json:"daily_word_count" db:"daily_word_count"`
 	CreatedAt    time.Time     `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time     `json:"updated_at" db:"updated_at"`
 }
 
+// ContentSource records how a content item entered the user's vocabulary.
+type ContentSource string
+
+const (
+	ContentSourceDaily       ContentSource = "daily"
+	ContentSourceTranslation ContentSource = "translation"
+	// ContentSourceFallback is served from a bundled word list or the review
+	// queue when the daily word couldn't be generated.
+	ContentSourceFallback ContentSource = "fallback"
+	// ContentSourceCustom is a word the user submitted themselves.
+	ContentSourceCustom ContentSource = "custom"
+	// ContentSourceImport came from a CSV/TSV vocabulary import.
+	ContentSourceImport ContentSource = "import"
+)
+
+// SkipReason is why a user skipped one of their daily words.
+type SkipReason string
+
+const (
+	SkipReasonKnown         SkipReason = "known"
+	SkipReasonTooHard       SkipReason = "too_hard"
+	SkipReasonInappropriate SkipReason = "inappropriate"
+	SkipReasonNotRelevant   SkipReason = "not_relevant"
+)
+
 type DailyContent struct {
 	ID          uuid.UUID `json:"id" db:"id"`
 	UserID      uuid.UUID `json:"user_id" db:"user_id"`
 	Date        time.Time `json:"date" db:"date"`
+	Position    int       `json:"position" db:"position"`
 	Word        string    `json:"word" db:"word"`
 	Meaning     string    `json:"meaning" db:"meaning"`
 	ExamplesTarget []string `json:"examples_target" db:"examples_target"`
 	ExamplesBase   []string `json:"examples_base,omitempty" db:"examples_base"`
+	LexicalInfo
+	Tags        []string  `json:"tags,omitempty" db:"tags"`
+	Source      ContentSource `json:"source" db:"source"`
+	LexiconEntryID *uuid.UUID `json:"lexicon_entry_id,omitempty" db:"lexicon_entry_id"`
+	SkipReason  *SkipReason `json:"skip_reason,omitempty" db:"skip_reason"`
+	SkippedAt   *time.Time `json:"skipped_at,omitempty" db:"skipped_at"`
+	Bookmarked  bool      `json:"bookmarked" db:"bookmarked"`
+	// Note is the user's own free-text note on the item.
+	Note        string    `json:"note,omitempty" db:"note"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 }
 
+// Bounds for Profile.DailyWordCount.
+const (
+	MinDailyWordCount = 1
+	MaxDailyWordCount = 10
+)
+
+// LexicalInfo is optional structured data about a word. Fields that don't
+// apply to the word or its language are left empty.
+type LexicalInfo struct {
+	PartOfSpeech string `json:"part_of_speech,omitempty" db:"part_of_speech"`
+	// Pronunciation is IPA between slashes, e.g. "/ˈka.sa/".
+	Pronunciation string `json:"pronunciation,omitempty" db:"pronunciation"`
+	// Romanization is given for languages written in a non-Latin script.
+	Romanization string `json:"romanization,omitempty" db:"romanization"`
+	// Gender is the grammatical gender of nouns in languages that have one.
+	Gender string `json:"gender,omitempty" db:"gender"`
+	// Inflections maps a form such as "plural" or "1sg present" to the word in that form.
+	Inflections map[string]string `json:"inflections,omitempty" db:"inflections"`
+}
+
+// DailyContentSet is the set of words a user studies on a date, ordered by
+// position. It holds fewer than the profile's daily word count when
+// generation fell short, and a single fallback item when nothing could be
+// generated.
+type DailyContentSet struct {
+	Date  time.Time       `json:"date"`
+	Items []*DailyContent `json:"items"`
+}
+
+// LexiconOrigin records whether a word bank entry was generated or curated.
+type LexiconOrigin string
+
+const (
+	LexiconOriginGenerated LexiconOrigin = "generated"
+	LexiconOriginCurated   LexiconOrigin = "curated"
+)
+
+// LexiconEntry is a word in the global word bank that daily content is drawn
+// from. Language entries set TargetLang and BaseLang, industry entries set Sector.
+type LexiconEntry struct {
+	ID             uuid.UUID     `json:"id" db:"id"`
+	TargetLang     string        `json:"target_lang,omitempty" db:"target_lang"`
+	BaseLang       string        `json:"base_lang,omitempty" db:"base_lang"`
+	Level          LanguageLevel `json:"level" db:"level"`
+	Sector         string        `json:"sector,omitempty" db:"sector"`
+	Lemma          string        `json:"lemma" db:"lemma"`
+	Word           string        `json:"word" db:"word"`
+	Meaning        string        `json:"meaning" db:"meaning"`
+	ExamplesTarget []string      `json:"examples_target" db:"examples_target"`
+	ExamplesBase   []string      `json:"examples_base,omitempty" db:"examples_base"`
+	LexicalInfo
+	Origin         LexiconOrigin `json:"origin" db:"origin"`
+	Active         bool          `json:"active" db:"active"`
+	CreatedAt      time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at" db:"updated_at"`
+}
+
 type QuizType string
 
 const (
 	QuizTypeMCQ        QuizType = "mcq"
 	QuizTypeFillBlank  QuizType = "fill_blank"
 	QuizTypeSituation  QuizType = "situation"
+	// QuizTypeSentence asks for a sentence that uses the word.
+	QuizTypeSentence QuizType = "sentence"
+	// QuizTypeMatching pairs the words in Terms with the meanings in
+	// Options. Answers are a JSON array of the chosen meanings, one per term
+	// in order.
+	QuizTypeMatching QuizType = "matching"
+	// QuizTypeWordOrder lists the words of an example sentence, scrambled, in
+	// Options. Answers are the sentence with the words in order.
+	QuizTypeWordOrder QuizType = "word_order"
+	// QuizTypeReverseTranslation asks for a base-language example sentence in
+	// the target language.
+	QuizTypeReverseTranslation QuizType = "reverse_translation"
+)
+
+// IsFreeText reports whether answers to the quiz type are open text graded
+// against a rubric rather than matched against one correct answer.
+func (t QuizType) IsFreeText() bool {
+	return t == QuizTypeSituation || t == QuizTypeSentence || t == QuizTypeReverseTranslation
+}
+
+// GeneratedQuiz is a quiz question with its answer, ready to be asked. Its
+// type may be simpler than the one asked for when that one couldn't be built.
+type GeneratedQuiz struct {
+	QuizType      QuizType
+	Question      string
+	Options       []string
+	Terms         []string
+	CorrectAnswer string
+}
+
+// AnswerGrade is how well a quiz answer matched the correct one. Close
+// answers, such as a typo or missing accent, earn partial credit.
+type AnswerGrade string
+
+const (
+	AnswerGradeExact AnswerGrade = "exact"
+	AnswerGradeClose AnswerGrade = "close"
+	AnswerGradeWrong AnswerGrade = "wrong"
 )
 
+// AnswerRubric is the criteria a free-text or matching answer was scored on.
+// Score is out of 100, and Credit is the share of it that counts toward
+// mastery.
+type AnswerRubric struct {
+	Score       int               `json:"score"`
+	Credit      float64           `json:"credit"`
+	Criteria    []RubricCriterion `json:"criteria"`
+	Explanation string            `json:"explanation"`
+}
+
+// ExplanationSource is how the explanation of a wrong answer was written.
+type ExplanationSource string
+
+const (
+	ExplanationSourceTemplate ExplanationSource = "template"
+	ExplanationSourceLLM      ExplanationSource = "llm"
+)
+
+// AnswerExplanation is corrective feedback on a wrong answer: why it is
+// wrong, how the word is used, an example sentence and a hint for next time.
+type AnswerExplanation struct {
+	WhyWrong     string            `json:"why_wrong"`
+	CorrectUsage string            `json:"correct_usage"`
+	Example      string            `json:"example,omitempty"`
+	Hint         string            `json:"hint,omitempty"`
+	Source       ExplanationSource `json:"source"`
+}
+
+type RubricCriterion struct {
+	Name    string `json:"name"`
+	Score   int    `json:"score"`
+	Max     int    `json:"max"`
+	Comment string `json:"comment,omitempty"`
+}
+
 type QuizLog struct {
 	ID          uuid.UUID `json:"id" db:"id"`
 	UserID      uuid.UUID `json:"user_id" db:"user_id"`
@@ -67,9 +238,18 @@ type QuizLog struct {
 	QuizType    QuizType  `json:"quiz_type" db:"quiz_type"`
 	Question    string    `json:"question" db:"question"`
 	Options     []string  `json:"options,omitempty" db:"options"`
+	Terms       []string  `json:"terms,omitempty" db:"terms"`
 	CorrectAnswer string  `json:"correct_answer" db:"correct_answer"`
 	UserAnswer  string    `json:"user_answer" db:"user_answer"`
 	IsCorrect   bool      `json:"is_correct" db:"is_correct"`
+	Grade       AnswerGrade `json:"grade" db:"grade"`
+	// Feedback explains a close or wrong answer, such as a missing accent.
+	Feedback    string    `json:"feedback,omitempty" db:"feedback"`
+	// Rubric is set for answers scored on several criteria: free-text answers,
+	// graded by the LLM, and matching answers, scored per pair.
+	Rubric      *AnswerRubric `json:"rubric,omitempty" db:"rubric"`
+	// Explanation is set on wrong answers when they are submitted.
+	Explanation *AnswerExplanation `json:"explanation,omitempty" db:"-"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 }
 
@@ -98,6 +278,53 @@ type PlanItem struct {
 	IsReviewDay bool        `json:"is_review_day"`
 }
 
+type LLMUsage struct {
+	ID               uuid.UUID  `json:"id" db:"id"`
+	UserID           *uuid.UUID `json:"user_id,omitempty" db:"user_id"`
+	Feature          string     `json:"feature" db:"feature"`
+	Model            string     `json:"model" db:"model"`
+	PromptTokens     int        `json:"prompt_tokens" db:"prompt_tokens"`
+	CandidatesTokens int        `json:"candidates_tokens" db:"candidates_tokens"`
+	TotalTokens      int        `json:"total_tokens" db:"total_tokens"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+}
+
+type UsageBudget struct {
+	ID                uuid.UUID  `json:"id" db:"id"`
+	Tier              *string    `json:"tier,omitempty" db:"tier"`
+	UserID            *uuid.UUID `json:"user_id,omitempty" db:"user_id"`
+	DailyTokenLimit   int        `json:"daily_token_limit" db:"daily_token_limit"`
+	MonthlyTokenLimit int        `json:"monthly_token_limit" db:"monthly_token_limit"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// UsageSummary is one aggregated row of the LLM usage ledger. Only the
+// dimensions requested in the grouping are set.
+type UsageSummary struct {
+	UserID           *uuid.UUID `json:"user_id,omitempty"`
+	Feature          string     `json:"feature,omitempty"`
+	Model            string     `json:"model,omitempty"`
+	Calls            int        `json:"calls"`
+	PromptTokens     int        `json:"prompt_tokens"`
+	CandidatesTokens int        `json:"candidates_tokens"`
+	TotalTokens      int        `json:"total_tokens"`
+}
+
+type TranslationMemory struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	UserID         uuid.UUID  `json:"user_id" db:"user_id"`
+	SourceText     string     `json:"source_text" db:"source_text"`
+	NormalizedText string     `json:"-" db:"normalized_text"`
+	BaseLang       string     `json:"base_lang" db:"base_lang"`
+	TargetLang     string     `json:"target_lang" db:"target_lang"`
+	Translation    string     `json:"translation" db:"translation"`
+	LookupCount    int        `json:"lookup_count" db:"lookup_count"`
+	ContentID      *uuid.UUID `json:"content_id,omitempty" db:"content_id"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	LastLookedUpAt time.Time  `json:"last_looked_up_at" db:"last_looked_up_at"`
+}
+
 // Request/Response DTOs
 type UpsertProfileRequest struct {
 	GoalType       GoalType      `json:"goal_type" binding:"required"`
@@ -105,6 +332,10 @@ type UpsertProfileRequest struct {
 	BaseLang       *string       `json:"base_lang,omitempty"`
 	Level          LanguageLevel `json:"level" binding:"required"`
 	IndustrySector *string       `json:"industry_sector,omitempty"`
+	Timezone       *string       `json:"timezone,omitempty"`
+	// DailyWordCount is how many words to serve per day, 1 to 10. Omitted
+	// keeps the current setting.
+	DailyWordCount *int `json:"daily_word_count,omitempty"`
 }
 
 type DailyContentRequest struct {
@@ -117,22 +348,130 @@ type QuizSubmissionRequest struct {
 	UserAnswer string    `json:"user_answer" binding:"required"`
 }
 
+type AddWordRequest struct {
+	Word string `json:"word" binding:"required"`
+	// Context is where the user met the word, such as the sentence it was
+	// used in. It helps pick the right sense.
+	Context string `json:"context,omitempty"`
+}
+
+// ImportJobStatus is the state of an asynchronous vocabulary import.
+type ImportJobStatus string
+
+const (
+	ImportJobStatusPending   ImportJobStatus = "pending"
+	ImportJobStatusRunning   ImportJobStatus = "running"
+	ImportJobStatusCompleted ImportJobStatus = "completed"
+	ImportJobStatusFailed    ImportJobStatus = "failed"
+)
+
+// ImportJob tracks a vocabulary import. Rows that duplicate words the user
+// already has are counted as skipped; rows that can't be imported are listed
+// in Errors by their line number in the file.
+type ImportJob struct {
+	ID         uuid.UUID        `json:"id" db:"id"`
+	UserID     uuid.UUID        `json:"user_id" db:"user_id"`
+	Status     ImportJobStatus  `json:"status" db:"status"`
+	Filename   string           `json:"filename" db:"filename"`
+	TotalRows  int              `json:"total_rows" db:"total_rows"`
+	Imported   int              `json:"imported" db:"imported"`
+	Skipped    int              `json:"skipped" db:"skipped"`
+	Failed     int              `json:"failed" db:"failed"`
+	Errors     []ImportRowError `json:"errors" db:"errors"`
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`
+	FinishedAt *time.Time       `json:"finished_at,omitempty" db:"finished_at"`
+}
+
+type ImportRowError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// UpdateContentRequest changes the user's annotations on a content item.
+// Fields left out are unchanged; Tags replaces all tags.
+type UpdateContentRequest struct {
+	Bookmarked *bool     `json:"bookmarked,omitempty"`
+	Note       *string   `json:"note,omitempty"`
+	Tags       *[]string `json:"tags,omitempty"`
+}
+
+type AddContentTagsRequest struct {
+	Tags []string `json:"tags" binding:"required"`
+}
+
+// TagCount is one of the user's tags and how many items carry it.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+type SkipDailyContentRequest struct {
+	ContentID uuid.UUID  `json:"content_id" binding:"required"`
+	Reason    SkipReason `json:"reason" binding:"required"`
+	// SeedMastery records a word skipped as known as already mastered, so it
+	// is only reviewed occasionally.
+	SeedMastery bool `json:"seed_mastery"`
+}
+
 type TranslateRequest struct {
 	Text       string `json:"text" binding:"required"`
 	TargetLang string `json:"target_lang" binding:"required"`
 	BaseLang   string `json:"base_lang" binding:"required"`
+	// Detail asks for alternatives, register variants, a grammar note and
+	// word glosses in addition to the translation. It costs more tokens.
+	Detail bool `json:"detail,omitempty"`
 }
 
 type TranslateResponse struct {
-	Translation string `json:"translation"`
+	ID           *uuid.UUID  `json:"id,omitempty"`
+	Translation  string      `json:"translation"`
+	Alternatives []string    `json:"alternatives,omitempty"`
+	Formal       string      `json:"formal,omitempty"`
+	Informal     string      `json:"informal,omitempty"`
+	GrammarNote  string      `json:"grammar_note,omitempty"`
+	Glosses      []WordGloss `json:"glosses,omitempty"`
+}
+
+type WordGloss struct {
+	Word  string `json:"word"`
+	Gloss string `json:"gloss"`
+}
+
+type CreateLexiconEntriesRequest struct {
+	Entries []LexiconEntryInput `json:"entries" binding:"required,min=1,max=500,dive"`
+}
+
+type LexiconEntryInput struct {
+	TargetLang     string        `json:"target_lang,omitempty"`
+	BaseLang       string        `json:"base_lang,omitempty"`
+	Level          LanguageLevel `json:"level" binding:"required"`
+	Sector         string        `json:"sector,omitempty"`
+	Word           string        `json:"word" binding:"required"`
+	Meaning        string        `json:"meaning" binding:"required"`
+	ExamplesTarget []string      `json:"examples_target" binding:"required,min=1"`
+	ExamplesBase   []string      `json:"examples_base,omitempty"`
+	LexicalInfo
+}
+
+type SetUsageBudgetRequest struct {
+	Tier              *string    `json:"tier,omitempty"`
+	UserID            *uuid.UUID `json:"user_id,omitempty"`
+	DailyTokenLimit   int        `json:"daily_token_limit" binding:"min=0"`
+	MonthlyTokenLimit int        `json:"monthly_token_limit" binding:"min=0"`
 }
 
 // Gemini API Response Structures
+// GeminiDailyContentBatchResponse wraps the words generated in one call.
+type GeminiDailyContentBatchResponse struct {
+	Words []GeminiDailyContentResponse `json:"words"`
+}
+
 type GeminiDailyContentResponse struct {
 	Word           string   `json:"word"`
 	Meaning        string   `json:"meaning"`
 	ExamplesTarget []string `json:"examples_target"`
 	ExamplesBase   []string `json:"examples_base,omitempty"`
+	LexicalInfo
 }
 
 type GeminiQuizResponse struct {
@@ -141,6 +480,195 @@ type GeminiQuizResponse struct {
 	CorrectAnswer string   `json:"correct_answer"`
 }
 
+// GeminiRubricResponse is the LLM's scoring of a free-text answer against
+// the rubric criteria.
+type GeminiRubricResponse struct {
+	Criteria    []RubricCriterion `json:"criteria"`
+	Explanation string            `json:"explanation"`
+}
+
+type GeminiExplanationResponse struct {
+	WhyWrong     string `json:"why_wrong"`
+	CorrectUsage string `json:"correct_usage"`
+	Example      string `json:"example"`
+	Hint         string `json:"hint"`
+}
+
 type GeminiTranslateResponse struct {
-	Translation string `json:"translation"`
+	Translation  string      `json:"translation"`
+	Alternatives []string    `json:"alternatives,omitempty"`
+	Formal       string      `json:"formal,omitempty"`
+	Informal     string      `json:"informal,omitempty"`
+	GrammarNote  string      `json:"grammar_note,omitempty"`
+	Glosses      []WordGloss `json:"glosses,omitempty"`
+}
+
+// ContentProgress is a content item with the user's progress on it.
+type ContentProgress struct {
+	*DailyContent
+	MasteryScore   *int       `json:"mastery_score"`
+	NextReviewDate *time.Time `json:"next_review_date"`
+	QuizAttempts   int        `json:"quiz_attempts"`
+	QuizCorrect    int        `json:"quiz_correct"`
+	// QuizAccuracy is the share of quiz answers that were correct, or nil if
+	// the item was never quizzed.
+	QuizAccuracy *float64 `json:"quiz_accuracy"`
+}
+
+// MasteryBucket groups content by mastery score, using the same thresholds
+// as the mastery stats.
+type MasteryBucket string
+
+const (
+	// MasteryBucketNew is content without a mastery record yet.
+	MasteryBucketNew      MasteryBucket = "new"
+	MasteryBucketWeak     MasteryBucket = "weak"
+	MasteryBucketModerate MasteryBucket = "moderate"
+	MasteryBucketStrong   MasteryBucket = "strong"
+)
+
+// ContentFilter narrows a content listing. Zero values don't filter.
+type ContentFilter struct {
+	From          *time.Time
+	To            *time.Time
+	MasteryBucket MasteryBucket
+	// MinAccuracy and MaxAccuracy bound quiz accuracy, from 0 to 1. Content
+	// that was never quizzed is excluded when either is set.
+	MinAccuracy *float64
+	MaxAccuracy *float64
+	// Tags keeps content carrying all of the tags.
+	Tags []string
+	// Bookmarked keeps only bookmarked content.
+	Bookmarked bool
+	// DueBy keeps content whose review is due on or before the date.
+	DueBy *time.Time
+}
+
+// ContentCursor is the position of the last item of a content page.
+type ContentCursor struct {
+	Date time.Time
+	ID   uuid.UUID
+}
+
+// SearchMatch is how a search result matched the query.
+type SearchMatch string
+
+const (
+	SearchMatchFullText SearchMatch = "fulltext"
+	// SearchMatchFuzzy results came from trigram matching, used when the
+	// query found nothing, typically because of a typo.
+	SearchMatchFuzzy SearchMatch = "fuzzy"
+)
+
+// ContentSearchResult is a content item matching a search.
+type ContentSearchResult struct {
+	*DailyContent
+	Rank  float64     `json:"rank"`
+	Match SearchMatch `json:"match"`
+	// Highlights hold the matched text with terms wrapped in <mark> tags.
+	// The surrounding text is not HTML-escaped. Fuzzy matches have none.
+	Highlights *ContentHighlights `json:"highlights,omitempty"`
+}
+
+type ContentHighlights struct {
+	Word     string `json:"word"`
+	Meaning  string `json:"meaning"`
+	Examples string `json:"examples,omitempty"`
+}
+
+type ReviewSessionStatus string
+
+const (
+	ReviewSessionStatusActive    ReviewSessionStatus = "active"
+	ReviewSessionStatusCompleted ReviewSessionStatus = "completed"
+	// ReviewSessionStatusAbandoned sessions were replaced by a new one
+	// before every question was answered.
+	ReviewSessionStatusAbandoned ReviewSessionStatus = "abandoned"
+)
+
+// ReviewSession is a run of quiz questions over words due for review.
+type ReviewSession struct {
+	ID          uuid.UUID             `json:"id" db:"id"`
+	UserID      uuid.UUID             `json:"user_id" db:"user_id"`
+	Status      ReviewSessionStatus   `json:"status" db:"status"`
+	Tags        []string              `json:"tags,omitempty" db:"tags"`
+	Items       []*ReviewSessionItem  `json:"items"`
+	Summary     *ReviewSessionSummary `json:"summary,omitempty"`
+	CreatedAt   time.Time             `json:"created_at" db:"created_at"`
+	CompletedAt *time.Time            `json:"completed_at,omitempty" db:"completed_at"`
+}
+
+// ReviewSessionItem is one question of a review session. The question is
+// filled in when it is first asked, and the correct answer is only shown
+// once the item is answered.
+type ReviewSessionItem struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	SessionID      uuid.UUID  `json:"-" db:"session_id"`
+	Position       int        `json:"position" db:"position"`
+	ContentID      uuid.UUID  `json:"content_id" db:"content_id"`
+	QuizType       QuizType   `json:"quiz_type" db:"quiz_type"`
+	Question       string     `json:"question,omitempty" db:"question"`
+	Options        []string   `json:"options,omitempty" db:"options"`
+	Terms          []string   `json:"terms,omitempty" db:"terms"`
+	CorrectAnswer  string     `json:"correct_answer,omitempty" db:"correct_answer"`
+	UserAnswer     *string    `json:"user_answer,omitempty" db:"user_answer"`
+	IsCorrect      *bool      `json:"is_correct,omitempty" db:"is_correct"`
+	Grade          AnswerGrade `json:"grade,omitempty" db:"grade"`
+	Feedback       string      `json:"feedback,omitempty" db:"feedback"`
+	Rubric         *AnswerRubric `json:"rubric,omitempty" db:"rubric"`
+	QuizLogID      *uuid.UUID `json:"quiz_log_id,omitempty" db:"quiz_log_id"`
+	MasteryBefore  *int       `json:"mastery_before,omitempty" db:"mastery_before"`
+	MasteryAfter   *int       `json:"mastery_after,omitempty" db:"mastery_after"`
+	NextReviewDate *time.Time `json:"next_review_date,omitempty" db:"next_review_date"`
+	AnsweredAt     *time.Time `json:"answered_at,omitempty" db:"answered_at"`
+}
+
+// ReviewSessionSummary totals the answered items of a session.
+type ReviewSessionSummary struct {
+	Total    int     `json:"total"`
+	Answered int     `json:"answered"`
+	// Correct counts exact answers; Close counts the close ones.
+	Correct  int     `json:"correct"`
+	Close    int     `json:"close"`
+	Accuracy float64 `json:"accuracy"`
+	// MasteryDelta is the summed change in mastery score over answered items.
+	MasteryDelta int `json:"mastery_delta"`
+	// NextReviewDate is the earliest next review among the answered items.
+	NextReviewDate *time.Time `json:"next_review_date,omitempty"`
+}
+
+// ReviewQuestion is the next unanswered question of a session.
+type ReviewQuestion struct {
+	SessionID uuid.UUID `json:"session_id"`
+	Position  int       `json:"position"`
+	Total     int       `json:"total"`
+	ContentID uuid.UUID `json:"content_id"`
+	QuizType  QuizType  `json:"quiz_type"`
+	Question  string    `json:"question"`
+	Options   []string  `json:"options,omitempty"`
+	Terms     []string  `json:"terms,omitempty"`
+}
+
+// ReviewAnswerResult is the graded answer to a session question.
+type ReviewAnswerResult struct {
+	Item    *ReviewSessionItem `json:"item"`
+	QuizLog *QuizLog           `json:"quiz_log"`
+	// Completed is set once the answer finished the session, which then
+	// carries its summary.
+	Completed bool           `json:"completed"`
+	Session   *ReviewSession `json:"session,omitempty"`
+}
+
+type StartReviewSessionRequest struct {
+	// Size is how many questions to ask; it defaults to 10.
+	Size int `json:"size,omitempty"`
+	// Tags limits the session to words carrying all of the tags.
+	Tags []string `json:"tags,omitempty"`
+	// Date is the client's local date, YYYY-MM-DD, used to decide which
+	// reviews are due. It defaults to today in the profile's timezone.
+	Date string `json:"date,omitempty"`
+}
+
+type SubmitReviewAnswerRequest struct {
+	Answer string `json:"answer" binding:"required"`
 }
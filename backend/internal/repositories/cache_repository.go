@@ -2,6 +2,8 @@ package repositories
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -20,10 +22,12 @@ func NewCacheRepository(client *redis.Client) *CacheRepository {
 	return &CacheRepository{client: client}
 }
 
-func (r *CacheRepository) GetDailyContent(ctx context.Context, userID uuid.UUID, date time.Time) (*models.DailyContent, error) {
-	key := fmt.Sprintf("daily_content:%s:%s", userID.String(), date.Format("2006-01-02"))
-	
-	data, err := r.client.Get(ctx, key).Result()
+func dailyContentCacheKey(userID uuid.UUID, date time.Time) string {
+	return fmt.Sprintf("daily_content_set:%s:%s", userID.String(), date.Format("2006-01-02"))
+}
+
+func (r *CacheRepository) GetDailyContent(ctx context.Context, userID uuid.UUID, date time.Time) (*models.DailyContentSet, error) {
+	data, err := r.client.Get(ctx, dailyContentCacheKey(userID, date)).Result()
 	if err == redis.Nil {
 		return nil, nil
 	}
@@ -31,30 +35,35 @@ func (r *CacheRepository) GetDailyContent(ctx context.Context, userID uuid.UUID,
 		return nil, fmt.Errorf("failed to get cached daily content: %w", err)
 	}
 
-	var content models.DailyContent
-	if err := json.Unmarshal([]byte(data), &content); err != nil {
+	var set models.DailyContentSet
+	if err := json.Unmarshal([]byte(data), &set); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal cached content: %w", err)
 	}
 
-	return &content, nil
+	return &set, nil
 }
 
-func (r *CacheRepository) SetDailyContent(ctx context.Context, content *models.DailyContent) error {
-	key := fmt.Sprintf("daily_content:%s:%s", content.UserID.String(), content.Date.Format("2006-01-02"))
-	
-	data, err := json.Marshal(content)
+func (r *CacheRepository) SetDailyContent(ctx context.Context, userID uuid.UUID, set *models.DailyContentSet) error {
+	data, err := json.Marshal(set)
 	if err != nil {
 		return fmt.Errorf("failed to marshal content for cache: %w", err)
 	}
 
 	// Cache for 24 hours
-	if err := r.client.Set(ctx, key, data, 24*time.Hour).Err(); err != nil {
+	if err := r.client.Set(ctx, dailyContentCacheKey(userID, set.Date), data, 24*time.Hour).Err(); err != nil {
 		return fmt.Errorf("failed to cache daily content: %w", err)
 	}
 
 	return nil
 }
 
+func (r *CacheRepository) DeleteDailyContent(ctx context.Context, userID uuid.UUID, date time.Time) error {
+	if err := r.client.Del(ctx, dailyContentCacheKey(userID, date)).Err(); err != nil {
+		return fmt.Errorf("failed to delete cached daily content: %w", err)
+	}
+	return nil
+}
+
 func (r *CacheRepository) IncrementRateLimit(ctx context.Context, userID uuid.UUID, endpoint string) (int, error) {
 	key := fmt.Sprintf("rate_limit:%s:%s:%s", userID.String(), endpoint, time.Now().Format("2006-01-02"))
 	
@@ -84,3 +93,92 @@ func (r *CacheRepository) GetRateLimit(ctx context.Context, userID uuid.UUID, en
 
 	return count, nil
 }
+
+// translationCacheKey keys a translation on the text as given, case
+// included. Keys before v2 folded case, so they can hold the translation of
+// another casing and aren't read.
+func translationCacheKey(text, baseLang, targetLang string, detail bool) string {
+	mode := "plain"
+	if detail {
+		mode = "detail"
+	}
+	sum := sha256.Sum256([]byte(text))
+	return fmt.Sprintf("translation:v2:%s:%s:%s:%s", mode, baseLang, targetLang, hex.EncodeToString(sum[:]))
+}
+
+func (r *CacheRepository) GetTranslation(ctx context.Context, text, baseLang, targetLang string, detail bool) (*models.GeminiTranslateResponse, error) {
+	key := translationCacheKey(text, baseLang, targetLang, detail)
+
+	data, err := r.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cached translation: %w", err)
+	}
+
+	var translation models.GeminiTranslateResponse
+	if err := json.Unmarshal([]byte(data), &translation); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cached translation: %w", err)
+	}
+
+	return &translation, nil
+}
+
+func (r *CacheRepository) SetTranslation(ctx context.Context, text, baseLang, targetLang string, detail bool, translation *models.GeminiTranslateResponse) error {
+	key := translationCacheKey(text, baseLang, targetLang, detail)
+
+	data, err := json.Marshal(translation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal translation for cache: %w", err)
+	}
+
+	// Translations don't change, so they can be kept for a long time
+	if err := r.client.Set(ctx, key, data, 30*24*time.Hour).Err(); err != nil {
+		return fmt.Errorf("failed to cache translation: %w", err)
+	}
+
+	return nil
+}
+
+func answerExplanationCacheKey(contentID uuid.UUID, quizType models.QuizType, question, normalizedAnswer string) string {
+	sum := sha256.Sum256([]byte(string(quizType) + "\x00" + question + "\x00" + normalizedAnswer))
+	return fmt.Sprintf("answer_explanation:%s:%s", contentID.String(), hex.EncodeToString(sum[:]))
+}
+
+// GetAnswerExplanation returns the cached explanation of a wrong answer to a
+// quiz question, or nil if there is none.
+func (r *CacheRepository) GetAnswerExplanation(ctx context.Context, contentID uuid.UUID, quizType models.QuizType, question, normalizedAnswer string) (*models.AnswerExplanation, error) {
+	key := answerExplanationCacheKey(contentID, quizType, question, normalizedAnswer)
+
+	data, err := r.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cached answer explanation: %w", err)
+	}
+
+	var explanation models.AnswerExplanation
+	if err := json.Unmarshal([]byte(data), &explanation); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cached answer explanation: %w", err)
+	}
+
+	return &explanation, nil
+}
+
+func (r *CacheRepository) SetAnswerExplanation(ctx context.Context, contentID uuid.UUID, quizType models.QuizType, question, normalizedAnswer string, explanation *models.AnswerExplanation) error {
+	key := answerExplanationCacheKey(contentID, quizType, question, normalizedAnswer)
+
+	data, err := json.Marshal(explanation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal answer explanation for cache: %w", err)
+	}
+
+	// The same wrong answer tends to come up again when a word is reviewed
+	if err := r.client.Set(ctx, key, data, 30*24*time.Hour).Err(); err != nil {
+		return fmt.Errorf("failed to cache answer explanation: %w", err)
+	}
+
+	return nil
+}
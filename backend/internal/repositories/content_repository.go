@@ -3,7 +3,10 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"lexipath-backend/internal/models"
@@ -20,25 +23,104 @@ func NewContentRepository(db *sql.DB) *ContentRepository {
 	return &ContentRepository{db: db}
 }
 
-func (r *ContentRepository) GetByUserAndDate(ctx context.Context, userID uuid.UUID, date time.Time) (*models.DailyContent, error) {
-	query := `
-		SELECT id, user_id, date, word, meaning, examples_target, examples_base, created_at
-		FROM daily_content
-		WHERE user_id = $1 AND date::date = $2::date
-	`
+const contentColumns = `id, user_id, date, position, word, meaning, examples_target, examples_base, part_of_speech, pronunciation, romanization, gender, inflections, tags, source, lexicon_entry_id, skip_reason, skipped_at, bookmarked, note, created_at`
+
+// qualifiedContentColumns is contentColumns prefixed with the dc alias, for
+// queries that join daily_content to other tables.
+var qualifiedContentColumns = "dc." + strings.ReplaceAll(contentColumns, ", ", ", dc.")
 
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanContent scans the contentColumns of a row, followed by any extra
+// columns the query selects into extra.
+func scanContent(row rowScanner, extra ...interface{}) (*models.DailyContent, error) {
 	var content models.DailyContent
-	err := r.db.QueryRowContext(ctx, query, userID, date).Scan(
+	var inflectionsJSON []byte
+	dest := []interface{}{
 		&content.ID,
 		&content.UserID,
 		&content.Date,
+		&content.Position,
 		&content.Word,
 		&content.Meaning,
 		pq.Array(&content.ExamplesTarget),
 		pq.Array(&content.ExamplesBase),
+		&content.PartOfSpeech,
+		&content.Pronunciation,
+		&content.Romanization,
+		&content.Gender,
+		&inflectionsJSON,
+		pq.Array(&content.Tags),
+		&content.Source,
+		&content.LexiconEntryID,
+		&content.SkipReason,
+		&content.SkippedAt,
+		&content.Bookmarked,
+		&content.Note,
 		&content.CreatedAt,
-	)
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(inflectionsJSON, &content.Inflections); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal inflections: %w", err)
+	}
+	return &content, nil
+}
 
+// marshalInflections encodes inflections for a JSONB column, storing an
+// empty object rather than null.
+func marshalInflections(inflections map[string]string) ([]byte, error) {
+	if inflections == nil {
+		inflections = map[string]string{}
+	}
+	data, err := json.Marshal(inflections)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal inflections: %w", err)
+	}
+	return data, nil
+}
+
+// ListByUserAndDate returns the user's daily words for the date in position
+// order. Skipped words are left out.
+func (r *ContentRepository) ListByUserAndDate(ctx context.Context, userID uuid.UUID, date time.Time) ([]*models.DailyContent, error) {
+	query := `
+		SELECT ` + contentColumns + `
+		FROM daily_content
+		WHERE user_id = $1 AND date::date = $2::date AND source = 'daily' AND skipped_at IS NULL
+		ORDER BY position
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily content: %w", err)
+	}
+	defer rows.Close()
+
+	var contents []*models.DailyContent
+	for rows.Next() {
+		content, err := scanContent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan content row: %w", err)
+		}
+		contents = append(contents, content)
+	}
+
+	return contents, nil
+}
+
+// GetByUserDateAndSource returns the user's item for the date from a source
+// that allows one item per day, such as fallback.
+func (r *ContentRepository) GetByUserDateAndSource(ctx context.Context, userID uuid.UUID, date time.Time, source models.ContentSource) (*models.DailyContent, error) {
+	query := `
+		SELECT ` + contentColumns + `
+		FROM daily_content
+		WHERE user_id = $1 AND date::date = $2::date AND source = $3
+	`
+
+	content, err := scanContent(r.db.QueryRowContext(ctx, query, userID, date, source))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -46,76 +128,527 @@ func (r *ContentRepository) GetByUserAndDate(ctx context.Context, userID uuid.UU
 		return nil, fmt.Errorf("failed to get daily content: %w", err)
 	}
 
-	return &content, nil
+	return content, nil
+}
+
+func (r *ContentRepository) GetByID(ctx context.Context, userID, contentID uuid.UUID) (*models.DailyContent, error) {
+	query := `
+		SELECT ` + contentColumns + `
+		FROM daily_content
+		WHERE user_id = $1 AND id = $2
+	`
+
+	content, err := scanContent(r.db.QueryRowContext(ctx, query, userID, contentID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get content: %w", err)
+	}
+
+	return content, nil
+}
+
+// normalizedAnswerSQL normalizes a column the way quiz answers are
+// normalized for grading: NFC, lower case, straight apostrophes, single
+// spaces and no trailing punctuation.
+func normalizedAnswerSQL(column string) string {
+	return `RTRIM(BTRIM(REGEXP_REPLACE(TRANSLATE(LOWER(NORMALIZE(` + column + `, NFC)), '’‘` + "`" + `', ''''''''), '\s+', ' ', 'g')), '.!?,;¡¿')`
+}
+
+// FindByWordOrMeaning returns another of the user's items whose word or
+// meaning is the answer, normalized for grading, or nil if there is none.
+func (r *ContentRepository) FindByWordOrMeaning(ctx context.Context, userID, excludeID uuid.UUID, answer string) (*models.DailyContent, error) {
+	query := `
+		SELECT ` + contentColumns + `
+		FROM daily_content
+		WHERE user_id = $1 AND id <> $2
+		  AND (` + normalizedAnswerSQL("word") + ` = $3 OR ` + normalizedAnswerSQL("meaning") + ` = $3)
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	content, err := scanContent(r.db.QueryRowContext(ctx, query, userID, excludeID, answer))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find content: %w", err)
+	}
+
+	return content, nil
+}
+
+// ListRecent returns the user's most recent items other than the excluded
+// one, newest first. Skipped words are left out.
+func (r *ContentRepository) ListRecent(ctx context.Context, userID, excludeID uuid.UUID, limit int) ([]*models.DailyContent, error) {
+	query := `
+		SELECT ` + contentColumns + `
+		FROM daily_content
+		WHERE user_id = $1 AND id <> $2 AND skipped_at IS NULL
+		ORDER BY date DESC, created_at DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, excludeID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent content: %w", err)
+	}
+	defer rows.Close()
+
+	var contents []*models.DailyContent
+	for rows.Next() {
+		content, err := scanContent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan content row: %w", err)
+		}
+		contents = append(contents, content)
+	}
+
+	return contents, nil
 }
 
 func (r *ContentRepository) Create(ctx context.Context, userID uuid.UUID, date time.Time, geminiResp *models.GeminiDailyContentResponse) (*models.DailyContent, error) {
 	content := &models.DailyContent{
-		ID:             uuid.New(),
 		UserID:         userID,
 		Date:           date,
 		Word:           geminiResp.Word,
 		Meaning:        geminiResp.Meaning,
 		ExamplesTarget: geminiResp.ExamplesTarget,
 		ExamplesBase:   geminiResp.ExamplesBase,
-		CreatedAt:      time.Now().UTC(),
+		Source:         models.ContentSourceDaily,
+	}
+
+	if err := r.Insert(ctx, content); err != nil {
+		return nil, err
+	}
+
+	return content, nil
+}
+
+// Insert stores a content item from any source. ID and CreatedAt are assigned here.
+func (r *ContentRepository) Insert(ctx context.Context, content *models.DailyContent) error {
+	content.ID = uuid.New()
+	content.CreatedAt = time.Now().UTC()
+	if content.ExamplesTarget == nil {
+		content.ExamplesTarget = []string{}
+	}
+	if content.Tags == nil {
+		content.Tags = []string{}
+	}
+
+	inflectionsJSON, err := marshalInflections(content.Inflections)
+	if err != nil {
+		return err
 	}
 
 	query := `
-		INSERT INTO daily_content (id, user_id, date, word, meaning, examples_target, examples_base, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO daily_content (` + contentColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err = r.db.ExecContext(ctx, query,
 		content.ID,
 		content.UserID,
 		content.Date,
+		content.Position,
 		content.Word,
 		content.Meaning,
 		pq.Array(content.ExamplesTarget),
 		pq.Array(content.ExamplesBase),
+		content.PartOfSpeech,
+		content.Pronunciation,
+		content.Romanization,
+		content.Gender,
+		inflectionsJSON,
+		pq.Array(content.Tags),
+		content.Source,
+		content.LexiconEntryID,
+		content.SkipReason,
+		content.SkippedAt,
+		content.Bookmarked,
+		content.Note,
 		content.CreatedAt,
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to create daily content: %w", err)
+		return fmt.Errorf("failed to create daily content: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateAnnotations sets the user's bookmark, note and tags on a content
+// item. Nil values are left unchanged. Returns nil if the item doesn't exist.
+func (r *ContentRepository) UpdateAnnotations(ctx context.Context, userID, contentID uuid.UUID, bookmarked *bool, note *string, tags []string) (*models.DailyContent, error) {
+	query := `
+		UPDATE daily_content
+		SET bookmarked = COALESCE($3::boolean, bookmarked),
+			note = COALESCE($4::text, note),
+			tags = COALESCE($5::text[], tags)
+		WHERE user_id = $1 AND id = $2
+		RETURNING ` + contentColumns
+
+	content, err := scanContent(r.db.QueryRowContext(ctx, query, userID, contentID, bookmarked, note, pq.Array(tags)))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update content annotations: %w", err)
 	}
 
 	return content, nil
 }
 
-func (r *ContentRepository) GetHistoryByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.DailyContent, error) {
+// ListTags returns the user's tags with how many items carry each, most
+// used first.
+func (r *ContentRepository) ListTags(ctx context.Context, userID uuid.UUID) ([]*models.TagCount, error) {
 	query := `
-		SELECT id, user_id, date, word, meaning, examples_target, examples_base, created_at
+		SELECT tag, COUNT(*)
+		FROM daily_content dc, unnest(dc.tags) AS tag
+		WHERE dc.user_id = $1 AND dc.skipped_at IS NULL
+		GROUP BY tag
+		ORDER BY COUNT(*) DESC, tag
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []*models.TagCount
+	for rows.Next() {
+		var tag models.TagCount
+		if err := rows.Scan(&tag.Tag, &tag.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, &tag)
+	}
+
+	return tags, nil
+}
+
+// MarkSkipped records that the user skipped a daily word, as long as they
+// have skipped fewer than limit words for its date. The user's daily words
+// for the date are locked while counting, so concurrent skips can't go past
+// the limit. Returns whether the word was skipped by this call, and whether
+// the limit was reached. A word that doesn't exist or was already skipped is
+// neither.
+func (r *ContentRepository) MarkSkipped(ctx context.Context, userID, contentID uuid.UUID, date time.Time, reason models.SkipReason, limit int) (bool, bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, false, fmt.Errorf("failed to begin skip transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	lockQuery := `
+		SELECT id, skipped_at IS NOT NULL
 		FROM daily_content
-		WHERE user_id = $1
-		ORDER BY date DESC
-		LIMIT $2 OFFSET $3
+		WHERE user_id = $1 AND date::date = $2::date AND source = 'daily'
+		FOR UPDATE
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	rows, err := tx.QueryContext(ctx, lockQuery, userID, date)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get content history: %w", err)
+		return false, false, fmt.Errorf("failed to lock daily content: %w", err)
 	}
 	defer rows.Close()
 
-	var contents []*models.DailyContent
+	found := false
+	skips := 0
 	for rows.Next() {
-		var content models.DailyContent
-		err := rows.Scan(
-			&content.ID,
-			&content.UserID,
-			&content.Date,
-			&content.Word,
-			&content.Meaning,
-			pq.Array(&content.ExamplesTarget),
-			pq.Array(&content.ExamplesBase),
-			&content.CreatedAt,
+		var id uuid.UUID
+		var skipped bool
+		if err := rows.Scan(&id, &skipped); err != nil {
+			return false, false, fmt.Errorf("failed to scan daily content: %w", err)
+		}
+		if skipped {
+			skips++
+			if id == contentID {
+				return false, false, nil
+			}
+		}
+		if id == contentID {
+			found = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return false, false, fmt.Errorf("failed to lock daily content: %w", err)
+	}
+	if !found {
+		return false, false, nil
+	}
+	if skips >= limit {
+		return false, true, nil
+	}
+
+	skipQuery := `
+		UPDATE daily_content
+		SET skip_reason = $3, skipped_at = NOW()
+		WHERE user_id = $1 AND id = $2
+	`
+
+	if _, err := tx.ExecContext(ctx, skipQuery, userID, contentID, reason); err != nil {
+		return false, false, fmt.Errorf("failed to skip content: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, false, fmt.Errorf("failed to commit skip: %w", err)
+	}
+
+	return true, false, nil
+}
+
+// ListSkippedLemmas returns the lemmas of words the user most recently
+// skipped for the reason in the given vocabulary scope.
+func (r *ContentRepository) ListSkippedLemmas(ctx context.Context, userID uuid.UUID, scope string, reason models.SkipReason, limit int) ([]string, error) {
+	query := `
+		SELECT ul.lemma
+		FROM daily_content dc
+		JOIN user_lemmas ul ON ul.content_id = dc.id AND ul.user_id = dc.user_id
+		WHERE dc.user_id = $1 AND ul.language = $2 AND dc.skip_reason = $3
+		ORDER BY dc.skipped_at DESC
+		LIMIT $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, scope, reason, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list skipped lemmas: %w", err)
+	}
+	defer rows.Close()
+
+	var lemmas []string
+	for rows.Next() {
+		var lemma string
+		if err := rows.Scan(&lemma); err != nil {
+			return nil, fmt.Errorf("failed to scan lemma: %w", err)
+		}
+		lemmas = append(lemmas, lemma)
+	}
+
+	return lemmas, nil
+}
+
+// ListWithProgress returns a page of the user's content, excluding skipped
+// words, with mastery and quiz results. Pages are ordered by date and id,
+// newest first when descending; pass the cursor of the last item of the
+// previous page as after, or nil for the first page.
+func (r *ContentRepository) ListWithProgress(ctx context.Context, userID uuid.UUID, filter models.ContentFilter, after *models.ContentCursor, descending bool, limit int) ([]*models.ContentProgress, error) {
+	args := []interface{}{userID}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	conditions := []string{"dc.user_id = $1", "dc.skipped_at IS NULL"}
+	if filter.From != nil {
+		conditions = append(conditions, "dc.date >= "+arg(*filter.From)+"::date")
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "dc.date <= "+arg(*filter.To)+"::date")
+	}
+	switch filter.MasteryBucket {
+	case models.MasteryBucketNew:
+		conditions = append(conditions, "m.id IS NULL")
+	case models.MasteryBucketWeak:
+		conditions = append(conditions, "m.mastery_score < 50")
+	case models.MasteryBucketModerate:
+		conditions = append(conditions, "m.mastery_score >= 50 AND m.mastery_score < 80")
+	case models.MasteryBucketStrong:
+		conditions = append(conditions, "m.mastery_score >= 80")
+	}
+	if filter.MinAccuracy != nil || filter.MaxAccuracy != nil {
+		conditions = append(conditions, "q.attempts > 0")
+	}
+	if filter.MinAccuracy != nil {
+		conditions = append(conditions, "q.correct::float / q.attempts >= "+arg(*filter.MinAccuracy))
+	}
+	if filter.MaxAccuracy != nil {
+		conditions = append(conditions, "q.correct::float / q.attempts <= "+arg(*filter.MaxAccuracy))
+	}
+
+	if len(filter.Tags) > 0 {
+		conditions = append(conditions, "dc.tags @> "+arg(pq.Array(filter.Tags))+"::text[]")
+	}
+	if filter.Bookmarked {
+		conditions = append(conditions, "dc.bookmarked")
+	}
+	if filter.DueBy != nil {
+		conditions = append(conditions, "m.next_review_date <= "+arg(*filter.DueBy)+"::date")
+	}
+
+	order := "dc.date, dc.id"
+	comparison := ">"
+	if descending {
+		order = "dc.date DESC, dc.id DESC"
+		comparison = "<"
+	}
+	if after != nil {
+		conditions = append(conditions, "(dc.date, dc.id) "+comparison+" ("+arg(after.Date)+"::date, "+arg(after.ID)+"::uuid)")
+	}
+
+	query := `
+		SELECT ` + qualifiedContentColumns + `, m.mastery_score, m.next_review_date,
+			COALESCE(q.attempts, 0), COALESCE(q.correct, 0)
+		FROM daily_content dc
+		LEFT JOIN mastery m ON m.user_id = dc.user_id AND m.content_id = dc.id
+		LEFT JOIN LATERAL (
+			SELECT COUNT(*) AS attempts, COUNT(*) FILTER (WHERE ql.is_correct) AS correct
+			FROM quiz_logs ql
+			WHERE ql.user_id = dc.user_id AND ql.content_id = dc.id
+		) q ON TRUE
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY ` + order + `
+		LIMIT ` + arg(limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list content with progress: %w", err)
+	}
+	defer rows.Close()
+
+	var items []*models.ContentProgress
+	for rows.Next() {
+		item := &models.ContentProgress{}
+		item.DailyContent, err = scanContent(rows, &item.MasteryScore, &item.NextReviewDate, &item.QuizAttempts, &item.QuizCorrect)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan content row: %w", err)
+		}
+		if item.QuizAttempts > 0 {
+			accuracy := float64(item.QuizCorrect) / float64(item.QuizAttempts)
+			item.QuizAccuracy = &accuracy
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list content with progress: %w", err)
+	}
+
+	return items, nil
+}
+
+// searchHighlightOptions configure ts_headline: whole fields are highlighted
+// for the word and meaning, and examples are cut down to their best fragments.
+const (
+	searchHighlightOptions = `StartSel=<mark>, StopSel=</mark>, HighlightAll=true`
+	searchSnippetOptions   = `StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=15, MinWords=5, FragmentDelimiter=" … "`
+)
+
+// Search runs a full-text search over the user's content. The query is
+// parsed with the configurations for both languages, since the word and
+// examples are in the target language and the meaning in the base one.
+func (r *ContentRepository) Search(ctx context.Context, userID uuid.UUID, targetLang, baseLang, query string, limit int) ([]*models.ContentSearchResult, error) {
+	sqlQuery := `
+		WITH search AS (
+			SELECT websearch_to_tsquery(search_config_for($2), $4) || websearch_to_tsquery(search_config_for($3), $4) AS query
 		)
+		SELECT ` + qualifiedContentColumns + `,
+			ts_rank_cd(dc.search_vector, search.query, 32) AS rank,
+			ts_headline(search_config_for($2), dc.word, search.query, '` + searchHighlightOptions + `'),
+			ts_headline(search_config_for($3), dc.meaning, search.query, '` + searchHighlightOptions + `'),
+			ts_headline(search_config_for($2), array_to_string(dc.examples_target, ' '), search.query, '` + searchSnippetOptions + `')
+		FROM daily_content dc, search
+		WHERE dc.user_id = $1 AND dc.skipped_at IS NULL AND dc.search_vector @@ search.query
+		ORDER BY rank DESC, dc.date DESC, dc.id
+		LIMIT $5
+	`
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, userID, targetLang, baseLang, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search content: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*models.ContentSearchResult
+	for rows.Next() {
+		result := &models.ContentSearchResult{Match: models.SearchMatchFullText, Highlights: &models.ContentHighlights{}}
+		result.DailyContent, err = scanContent(rows, &result.Rank, &result.Highlights.Word, &result.Highlights.Meaning, &result.Highlights.Examples)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search content: %w", err)
+	}
+
+	return results, nil
+}
+
+// SearchFuzzy finds content whose word or meaning is similar to the query by
+// trigrams, which tolerates typos that full-text search misses.
+func (r *ContentRepository) SearchFuzzy(ctx context.Context, userID uuid.UUID, query string, limit int) ([]*models.ContentSearchResult, error) {
+	sqlQuery := `
+		SELECT ` + qualifiedContentColumns + `,
+			GREATEST(similarity(dc.word, $2), word_similarity($2, dc.meaning)) AS rank
+		FROM daily_content dc
+		WHERE dc.user_id = $1 AND dc.skipped_at IS NULL AND (dc.word % $2 OR $2 <% dc.meaning)
+		ORDER BY rank DESC, dc.date DESC, dc.id
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, userID, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fuzzy search content: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*models.ContentSearchResult
+	for rows.Next() {
+		result := &models.ContentSearchResult{Match: models.SearchMatchFuzzy}
+		result.DailyContent, err = scanContent(rows, &result.Rank)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to fuzzy search content: %w", err)
+	}
+
+	return results, nil
+}
+
+// ListForReview returns the user's content to quiz on the date: reviews due
+// first, then weak words, then words never quizzed. Only content carrying
+// all of the tags is returned.
+func (r *ContentRepository) ListForReview(ctx context.Context, userID uuid.UUID, date time.Time, tags []string, limit int) ([]*models.DailyContent, error) {
+	if tags == nil {
+		tags = []string{}
+	}
+
+	query := `
+		SELECT ` + qualifiedContentColumns + `
+		FROM daily_content dc
+		LEFT JOIN mastery m ON m.user_id = dc.user_id AND m.content_id = dc.id
+		WHERE dc.user_id = $1 AND dc.skipped_at IS NULL AND dc.tags @> $3::text[]
+			AND (m.id IS NULL OR m.next_review_date <= $2::date OR m.mastery_score < 50)
+		ORDER BY
+			CASE
+				WHEN m.next_review_date <= $2::date THEN 0
+				WHEN m.id IS NOT NULL THEN 1
+				ELSE 2
+			END,
+			m.next_review_date NULLS LAST,
+			m.mastery_score,
+			dc.date DESC
+		LIMIT $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, date, pq.Array(tags), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list content for review: %w", err)
+	}
+	defer rows.Close()
+
+	var contents []*models.DailyContent
+	for rows.Next() {
+		content, err := scanContent(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan content row: %w", err)
 		}
-		contents = append(contents, &content)
+		contents = append(contents, content)
 	}
 
 	return contents, nil
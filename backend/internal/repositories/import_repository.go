@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"lexipath-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type ImportRepository struct {
+	db *sql.DB
+}
+
+func NewImportRepository(db *sql.DB) *ImportRepository {
+	return &ImportRepository{db: db}
+}
+
+const importJobColumns = `id, user_id, status, filename, total_rows, imported, skipped, failed, errors, created_at, finished_at`
+
+func (r *ImportRepository) Create(ctx context.Context, userID uuid.UUID, filename string, totalRows int) (*models.ImportJob, error) {
+	job := &models.ImportJob{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Status:    models.ImportJobStatusPending,
+		Filename:  filename,
+		TotalRows: totalRows,
+		Errors:    []models.ImportRowError{},
+		CreatedAt: time.Now().UTC(),
+	}
+
+	query := `
+		INSERT INTO import_jobs (id, user_id, status, filename, total_rows, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, job.ID, job.UserID, job.Status, job.Filename, job.TotalRows, job.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create import job: %w", err)
+	}
+
+	return job, nil
+}
+
+func (r *ImportRepository) GetByID(ctx context.Context, userID, jobID uuid.UUID) (*models.ImportJob, error) {
+	query := `
+		SELECT ` + importJobColumns + `
+		FROM import_jobs
+		WHERE user_id = $1 AND id = $2
+	`
+
+	var job models.ImportJob
+	var errorsJSON []byte
+	err := r.db.QueryRowContext(ctx, query, userID, jobID).Scan(
+		&job.ID,
+		&job.UserID,
+		&job.Status,
+		&job.Filename,
+		&job.TotalRows,
+		&job.Imported,
+		&job.Skipped,
+		&job.Failed,
+		&errorsJSON,
+		&job.CreatedAt,
+		&job.FinishedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import job: %w", err)
+	}
+
+	if err := json.Unmarshal(errorsJSON, &job.Errors); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal import errors: %w", err)
+	}
+
+	return &job, nil
+}
+
+// Update saves the job's status, counters and row errors.
+func (r *ImportRepository) Update(ctx context.Context, job *models.ImportJob) error {
+	errorsJSON, err := json.Marshal(job.Errors)
+	if err != nil {
+		return fmt.Errorf("failed to marshal import errors: %w", err)
+	}
+
+	query := `
+		UPDATE import_jobs
+		SET status = $2, imported = $3, skipped = $4, failed = $5, errors = $6, finished_at = $7
+		WHERE id = $1
+	`
+
+	_, err = r.db.ExecContext(ctx, query, job.ID, job.Status, job.Imported, job.Skipped, job.Failed, errorsJSON, job.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update import job: %w", err)
+	}
+
+	return nil
+}
+
+// FailInterrupted marks jobs left pending or running by a previous process as
+// failed, since nothing will pick them up again. Returns how many were marked.
+func (r *ImportRepository) FailInterrupted(ctx context.Context) (int, error) {
+	query := `
+		UPDATE import_jobs
+		SET status = 'failed', finished_at = NOW()
+		WHERE status IN ('pending', 'running')
+	`
+
+	result, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail interrupted import jobs: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail interrupted import jobs: %w", err)
+	}
+
+	return int(affected), nil
+}
@@ -0,0 +1,165 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type LemmaRepository struct {
+	db *sql.DB
+}
+
+func NewLemmaRepository(db *sql.DB) *LemmaRepository {
+	return &LemmaRepository{db: db}
+}
+
+func (r *LemmaRepository) Exists(ctx context.Context, userID uuid.UUID, language, lemma string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM user_lemmas WHERE user_id = $1 AND language = $2 AND lemma = $3)`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, userID, language, lemma).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check lemma: %w", err)
+	}
+
+	return exists, nil
+}
+
+// GetContentID returns the content item that introduced the lemma to the
+// user, or nil if the lemma isn't indexed or its content was deleted.
+func (r *LemmaRepository) GetContentID(ctx context.Context, userID uuid.UUID, language, lemma string) (*uuid.UUID, error) {
+	query := `SELECT content_id FROM user_lemmas WHERE user_id = $1 AND language = $2 AND lemma = $3`
+
+	var contentID *uuid.UUID
+	err := r.db.QueryRowContext(ctx, query, userID, language, lemma).Scan(&contentID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lemma content: %w", err)
+	}
+
+	return contentID, nil
+}
+
+// ListRecent returns the user's most recently seen lemmas for a language.
+func (r *LemmaRepository) ListRecent(ctx context.Context, userID uuid.UUID, language string, limit int) ([]string, error) {
+	query := `
+		SELECT lemma
+		FROM user_lemmas
+		WHERE user_id = $1 AND language = $2
+		ORDER BY last_seen_at DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, language, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent lemmas: %w", err)
+	}
+	defer rows.Close()
+
+	var lemmas []string
+	for rows.Next() {
+		var lemma string
+		if err := rows.Scan(&lemma); err != nil {
+			return nil, fmt.Errorf("failed to scan lemma: %w", err)
+		}
+		lemmas = append(lemmas, lemma)
+	}
+
+	return lemmas, nil
+}
+
+// Add records that the user has seen a lemma, linking it to the content item
+// that introduced it. Seeing it again only refreshes last_seen_at.
+func (r *LemmaRepository) Add(ctx context.Context, userID uuid.UUID, language, lemma string, contentID *uuid.UUID) error {
+	now := time.Now().UTC()
+
+	query := `
+		INSERT INTO user_lemmas (id, user_id, language, lemma, content_id, first_seen_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (user_id, language, lemma)
+		DO UPDATE SET last_seen_at = EXCLUDED.last_seen_at
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, uuid.New(), userID, language, lemma, contentID, now); err != nil {
+		return fmt.Errorf("failed to add lemma: %w", err)
+	}
+
+	return nil
+}
+
+// ListLeadingArticle returns the words of the content items that introduced
+// a language's lemmas starting with one of the articles, keyed by lemma ID.
+// Articles ending in an apostrophe attach to the word; the others must be
+// followed by a space and another word.
+func (r *LemmaRepository) ListLeadingArticle(ctx context.Context, language string, articles []string) (map[uuid.UUID]string, error) {
+	patterns := make([]string, 0, len(articles))
+	for _, article := range articles {
+		if strings.HasSuffix(article, "'") {
+			patterns = append(patterns, article+"_%")
+		} else {
+			patterns = append(patterns, article+" _%")
+		}
+	}
+
+	query := `
+		SELECT ul.id, dc.word
+		FROM user_lemmas ul
+		JOIN daily_content dc ON dc.id = ul.content_id
+		WHERE ul.language = $1 AND ul.lemma LIKE ANY($2)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, language, pq.Array(patterns))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lemmas with articles: %w", err)
+	}
+	defer rows.Close()
+
+	words := make(map[uuid.UUID]string)
+	for rows.Next() {
+		var id uuid.UUID
+		var word string
+		if err := rows.Scan(&id, &word); err != nil {
+			return nil, fmt.Errorf("failed to scan lemma: %w", err)
+		}
+		words[id] = word
+	}
+
+	return words, nil
+}
+
+// Rename changes a lemma, reporting whether it was different. If the user
+// already has the new lemma, the two are merged, keeping the earliest and
+// latest sightings.
+func (r *LemmaRepository) Rename(ctx context.Context, id uuid.UUID, lemma string) (bool, error) {
+	query := `
+		WITH renamed AS (
+			DELETE FROM user_lemmas WHERE id = $1 AND lemma <> $2
+			RETURNING id, user_id, language, content_id, first_seen_at, last_seen_at
+		)
+		INSERT INTO user_lemmas (id, user_id, language, lemma, content_id, first_seen_at, last_seen_at)
+		SELECT id, user_id, language, $2, content_id, first_seen_at, last_seen_at FROM renamed
+		ON CONFLICT (user_id, language, lemma)
+		DO UPDATE SET
+			first_seen_at = LEAST(user_lemmas.first_seen_at, EXCLUDED.first_seen_at),
+			last_seen_at = GREATEST(user_lemmas.last_seen_at, EXCLUDED.last_seen_at)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, lemma)
+	if err != nil {
+		return false, fmt.Errorf("failed to rename lemma: %w", err)
+	}
+
+	renamed, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get renamed lemmas: %w", err)
+	}
+
+	return renamed > 0, nil
+}
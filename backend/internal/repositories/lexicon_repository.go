@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"lexipath-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type LexiconRepository struct {
+	db *sql.DB
+}
+
+func NewLexiconRepository(db *sql.DB) *LexiconRepository {
+	return &LexiconRepository{db: db}
+}
+
+const lexiconColumns = `id, target_lang, base_lang, level, sector, lemma, word, meaning, examples_target, examples_base, part_of_speech, pronunciation, romanization, gender, inflections, origin, active, created_at, updated_at`
+
+func scanLexiconEntry(row rowScanner) (*models.LexiconEntry, error) {
+	var entry models.LexiconEntry
+	var inflectionsJSON []byte
+	err := row.Scan(
+		&entry.ID,
+		&entry.TargetLang,
+		&entry.BaseLang,
+		&entry.Level,
+		&entry.Sector,
+		&entry.Lemma,
+		&entry.Word,
+		&entry.Meaning,
+		pq.Array(&entry.ExamplesTarget),
+		pq.Array(&entry.ExamplesBase),
+		&entry.PartOfSpeech,
+		&entry.Pronunciation,
+		&entry.Romanization,
+		&entry.Gender,
+		&inflectionsJSON,
+		&entry.Origin,
+		&entry.Active,
+		&entry.CreatedAt,
+		&entry.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(inflectionsJSON, &entry.Inflections); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal inflections: %w", err)
+	}
+	return &entry, nil
+}
+
+// GetUnseenEntry picks a random active entry for the catalog key whose lemma
+// the user hasn't been taught in the given vocabulary scope.
+func (r *LexiconRepository) GetUnseenEntry(ctx context.Context, userID uuid.UUID, scope string, key *models.LexiconEntry) (*models.LexiconEntry, error) {
+	query := `
+		SELECT ` + lexiconColumns + `
+		FROM lexicon_entries le
+		WHERE le.target_lang = $1 AND le.base_lang = $2 AND le.level = $3 AND le.sector = $4
+		AND le.active
+		AND NOT EXISTS (
+			SELECT 1 FROM user_lemmas ul
+			WHERE ul.user_id = $5 AND ul.language = $6 AND ul.lemma = le.lemma
+		)
+		ORDER BY random()
+		LIMIT 1
+	`
+
+	entry, err := scanLexiconEntry(r.db.QueryRowContext(ctx, query,
+		key.TargetLang, key.BaseLang, key.Level, key.Sector, userID, scope))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unseen lexicon entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+// Upsert adds an entry to the word bank. An existing entry with the same key
+// and lemma is only overwritten by curated content, so generated words never
+// replace curated ones.
+func (r *LexiconRepository) Upsert(ctx context.Context, entry *models.LexiconEntry) error {
+	now := time.Now().UTC()
+	entry.ID = uuid.New()
+	entry.Active = true
+	entry.CreatedAt = now
+	entry.UpdatedAt = now
+	if entry.ExamplesTarget == nil {
+		entry.ExamplesTarget = []string{}
+	}
+
+	inflectionsJSON, err := marshalInflections(entry.Inflections)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO lexicon_entries (` + lexiconColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		ON CONFLICT (target_lang, base_lang, level, sector, lemma)
+		DO UPDATE SET
+			word = EXCLUDED.word,
+			meaning = EXCLUDED.meaning,
+			examples_target = EXCLUDED.examples_target,
+			examples_base = EXCLUDED.examples_base,
+			part_of_speech = EXCLUDED.part_of_speech,
+			pronunciation = EXCLUDED.pronunciation,
+			romanization = EXCLUDED.romanization,
+			gender = EXCLUDED.gender,
+			inflections = EXCLUDED.inflections,
+			origin = EXCLUDED.origin,
+			active = TRUE,
+			updated_at = EXCLUDED.updated_at
+		WHERE EXCLUDED.origin = 'curated' OR lexicon_entries.origin <> 'curated'
+		RETURNING id, created_at
+	`
+
+	err = r.db.QueryRowContext(ctx, query,
+		entry.ID,
+		entry.TargetLang,
+		entry.BaseLang,
+		entry.Level,
+		entry.Sector,
+		entry.Lemma,
+		entry.Word,
+		entry.Meaning,
+		pq.Array(entry.ExamplesTarget),
+		pq.Array(entry.ExamplesBase),
+		entry.PartOfSpeech,
+		entry.Pronunciation,
+		entry.Romanization,
+		entry.Gender,
+		inflectionsJSON,
+		entry.Origin,
+		entry.Active,
+		entry.CreatedAt,
+		entry.UpdatedAt,
+	).Scan(&entry.ID, &entry.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		// Conflict with a curated entry that was left untouched
+		err = r.db.QueryRowContext(ctx, `
+			SELECT id, created_at FROM lexicon_entries
+			WHERE target_lang = $1 AND base_lang = $2 AND level = $3 AND sector = $4 AND lemma = $5
+		`, entry.TargetLang, entry.BaseLang, entry.Level, entry.Sector, entry.Lemma).Scan(&entry.ID, &entry.CreatedAt)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to upsert lexicon entry: %w", err)
+	}
+
+	return nil
+}
+
+// SetActive enables or disables an entry. Returns false if it doesn't exist.
+func (r *LexiconRepository) SetActive(ctx context.Context, id uuid.UUID, active bool) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `UPDATE lexicon_entries SET active = $2 WHERE id = $1`, id, active)
+	if err != nil {
+		return false, fmt.Errorf("failed to update lexicon entry: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to update lexicon entry: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// ReportInappropriate counts a user report against an entry. Generated entries
+// are disabled once they reach the threshold; curated ones are left for an
+// admin to review.
+func (r *LexiconRepository) ReportInappropriate(ctx context.Context, id uuid.UUID, threshold int) error {
+	query := `
+		UPDATE lexicon_entries
+		SET inappropriate_reports = inappropriate_reports + 1,
+			active = CASE
+				WHEN origin = 'generated' AND inappropriate_reports + 1 >= $2 THEN FALSE
+				ELSE active
+			END
+		WHERE id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, id, threshold); err != nil {
+		return fmt.Errorf("failed to report lexicon entry: %w", err)
+	}
+
+	return nil
+}
@@ -19,15 +19,11 @@ func NewProfileRepository(db *sql.DB) *ProfileRepository {
 	return &ProfileRepository{db: db}
 }
 
-func (r *ProfileRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*models.Profile, error) {
-	query := `
-		SELECT id, user_id, goal_type, target_lang, base_lang, level, industry_sector, created_at, updated_at
-		FROM profiles
-		WHERE user_id = $1
-	`
+const profileColumns = `id, user_id, goal_type, target_lang, base_lang, level, industry_sector, timezone, daily_word_count, created_at, updated_at`
 
+func scanProfile(row rowScanner) (*models.Profile, error) {
 	var profile models.Profile
-	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+	err := row.Scan(
 		&profile.ID,
 		&profile.UserID,
 		&profile.GoalType,
@@ -35,10 +31,25 @@ func (r *ProfileRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (
 		&profile.BaseLang,
 		&profile.Level,
 		&profile.IndustrySector,
+		&profile.Timezone,
+		&profile.DailyWordCount,
 		&profile.CreatedAt,
 		&profile.UpdatedAt,
 	)
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
 
+func (r *ProfileRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*models.Profile, error) {
+	query := `
+		SELECT ` + profileColumns + `
+		FROM profiles
+		WHERE user_id = $1
+	`
+
+	profile, err := scanProfile(r.db.QueryRowContext(ctx, query, userID))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -46,7 +57,37 @@ func (r *ProfileRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (
 		return nil, fmt.Errorf("failed to get profile by user_id: %w", err)
 	}
 
-	return &profile, nil
+	return profile, nil
+}
+
+// ListActive returns the profiles of users who have received content since
+// the given time.
+func (r *ProfileRepository) ListActive(ctx context.Context, since time.Time) ([]*models.Profile, error) {
+	query := `
+		SELECT ` + profileColumns + `
+		FROM profiles p
+		WHERE EXISTS (
+			SELECT 1 FROM daily_content dc
+			WHERE dc.user_id = p.user_id AND dc.created_at >= $1
+		)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var profiles []*models.Profile
+	for rows.Next() {
+		profile, err := scanProfile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan profile row: %w", err)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, nil
 }
 
 func (r *ProfileRepository) Upsert(ctx context.Context, userID uuid.UUID, req *models.UpsertProfileRequest) (*models.Profile, error) {
@@ -58,13 +99,14 @@ func (r *ProfileRepository) Upsert(ctx context.Context, userID uuid.UUID, req *m
 		BaseLang:       req.BaseLang,
 		Level:          req.Level,
 		IndustrySector: req.IndustrySector,
+		Timezone:       req.Timezone,
 		CreatedAt:      time.Now().UTC(),
 		UpdatedAt:      time.Now().UTC(),
 	}
 
 	query := `
-		INSERT INTO profiles (id, user_id, goal_type, target_lang, base_lang, level, industry_sector, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO profiles (` + profileColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE($9::smallint, 1), $10, $11)
 		ON CONFLICT (user_id)
 		DO UPDATE SET
 			goal_type = EXCLUDED.goal_type,
@@ -72,8 +114,10 @@ func (r *ProfileRepository) Upsert(ctx context.Context, userID uuid.UUID, req *m
 			base_lang = EXCLUDED.base_lang,
 			level = EXCLUDED.level,
 			industry_sector = EXCLUDED.industry_sector,
+			timezone = EXCLUDED.timezone,
+			daily_word_count = COALESCE($9::smallint, profiles.daily_word_count),
 			updated_at = EXCLUDED.updated_at
-		RETURNING id, created_at
+		RETURNING id, daily_word_count, created_at
 	`
 
 	err := r.db.QueryRowContext(ctx, query,
@@ -84,9 +128,11 @@ func (r *ProfileRepository) Upsert(ctx context.Context, userID uuid.UUID, req *m
 		profile.BaseLang,
 		profile.Level,
 		profile.IndustrySector,
+		profile.Timezone,
+		req.DailyWordCount,
 		profile.CreatedAt,
 		profile.UpdatedAt,
-	).Scan(&profile.ID, &profile.CreatedAt)
+	).Scan(&profile.ID, &profile.DailyWordCount, &profile.CreatedAt)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to upsert profile: %w", err)
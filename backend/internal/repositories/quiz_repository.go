@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -20,48 +21,54 @@ func NewQuizRepository(db *sql.DB) *QuizRepository {
 	return &QuizRepository{db: db}
 }
 
-func (r *QuizRepository) Create(ctx context.Context, userID, contentID uuid.UUID, quizType models.QuizType, question string, options []string, correctAnswer, userAnswer string, isCorrect bool) (*models.QuizLog, error) {
-	quiz := &models.QuizLog{
-		ID:            uuid.New(),
-		UserID:        userID,
-		ContentID:     contentID,
-		QuizType:      quizType,
-		Question:      question,
-		Options:       options,
-		CorrectAnswer: correctAnswer,
-		UserAnswer:    userAnswer,
-		IsCorrect:     isCorrect,
-		CreatedAt:     time.Now().UTC(),
+const quizLogColumns = `id, user_id, content_id, quiz_type, question, options, terms, correct_answer, user_answer, is_correct, grade, feedback, rubric, created_at`
+
+// Create stores a graded quiz answer, filling in its ID and creation time.
+func (r *QuizRepository) Create(ctx context.Context, quiz *models.QuizLog) error {
+	quiz.ID = uuid.New()
+	quiz.CreatedAt = time.Now().UTC()
+
+	if quiz.Terms == nil {
+		quiz.Terms = []string{}
+	}
+
+	rubricJSON, err := marshalRubric(quiz.Rubric)
+	if err != nil {
+		return err
 	}
 
 	query := `
-		INSERT INTO quiz_logs (id, user_id, content_id, quiz_type, question, options, correct_answer, user_answer, is_correct, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		INSERT INTO quiz_logs (` + quizLogColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err = r.db.ExecContext(ctx, query,
 		quiz.ID,
 		quiz.UserID,
 		quiz.ContentID,
 		quiz.QuizType,
 		quiz.Question,
 		pq.Array(quiz.Options),
+		pq.Array(quiz.Terms),
 		quiz.CorrectAnswer,
 		quiz.UserAnswer,
 		quiz.IsCorrect,
+		quiz.Grade,
+		quiz.Feedback,
+		rubricJSON,
 		quiz.CreatedAt,
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to create quiz log: %w", err)
+		return fmt.Errorf("failed to create quiz log: %w", err)
 	}
 
-	return quiz, nil
+	return nil
 }
 
 func (r *QuizRepository) GetByUserAndContent(ctx context.Context, userID, contentID uuid.UUID) ([]*models.QuizLog, error) {
 	query := `
-		SELECT id, user_id, content_id, quiz_type, question, options, correct_answer, user_answer, is_correct, created_at
+		SELECT ` + quizLogColumns + `
 		FROM quiz_logs
 		WHERE user_id = $1 AND content_id = $2
 		ORDER BY created_at DESC
@@ -76,6 +83,7 @@ func (r *QuizRepository) GetByUserAndContent(ctx context.Context, userID, conten
 	var quizzes []*models.QuizLog
 	for rows.Next() {
 		var quiz models.QuizLog
+		var rubricJSON []byte
 		err := rows.Scan(
 			&quiz.ID,
 			&quiz.UserID,
@@ -83,16 +91,46 @@ func (r *QuizRepository) GetByUserAndContent(ctx context.Context, userID, conten
 			&quiz.QuizType,
 			&quiz.Question,
 			pq.Array(&quiz.Options),
+			pq.Array(&quiz.Terms),
 			&quiz.CorrectAnswer,
 			&quiz.UserAnswer,
 			&quiz.IsCorrect,
+			&quiz.Grade,
+			&quiz.Feedback,
+			&rubricJSON,
 			&quiz.CreatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan quiz row: %w", err)
 		}
+		if quiz.Rubric, err = unmarshalRubric(rubricJSON); err != nil {
+			return nil, err
+		}
 		quizzes = append(quizzes, &quiz)
 	}
 
 	return quizzes, nil
 }
+
+// marshalRubric encodes a rubric for a nullable JSONB column.
+func marshalRubric(rubric *models.AnswerRubric) ([]byte, error) {
+	if rubric == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(rubric)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rubric: %w", err)
+	}
+	return data, nil
+}
+
+func unmarshalRubric(data []byte) (*models.AnswerRubric, error) {
+	if data == nil {
+		return nil, nil
+	}
+	var rubric models.AnswerRubric
+	if err := json.Unmarshal(data, &rubric); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rubric: %w", err)
+	}
+	return &rubric, nil
+}
@@ -0,0 +1,307 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"lexipath-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type ReviewSessionRepository struct {
+	db *sql.DB
+}
+
+func NewReviewSessionRepository(db *sql.DB) *ReviewSessionRepository {
+	return &ReviewSessionRepository{db: db}
+}
+
+const reviewSessionColumns = `id, user_id, status, tags, created_at, completed_at`
+
+const reviewItemColumns = `id, session_id, position, content_id, quiz_type, question, options, terms, correct_answer, user_answer, is_correct, grade, feedback, rubric, quiz_log_id, mastery_before, mastery_after, next_review_date, answered_at`
+
+func scanReviewItem(row rowScanner) (*models.ReviewSessionItem, error) {
+	var item models.ReviewSessionItem
+	var question, correctAnswer, grade sql.NullString
+	var rubricJSON []byte
+	err := row.Scan(
+		&item.ID,
+		&item.SessionID,
+		&item.Position,
+		&item.ContentID,
+		&item.QuizType,
+		&question,
+		pq.Array(&item.Options),
+		pq.Array(&item.Terms),
+		&correctAnswer,
+		&item.UserAnswer,
+		&item.IsCorrect,
+		&grade,
+		&item.Feedback,
+		&rubricJSON,
+		&item.QuizLogID,
+		&item.MasteryBefore,
+		&item.MasteryAfter,
+		&item.NextReviewDate,
+		&item.AnsweredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	item.Question = question.String
+	item.CorrectAnswer = correctAnswer.String
+	item.Grade = models.AnswerGrade(grade.String)
+	if item.Rubric, err = unmarshalRubric(rubricJSON); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Create stores a new session with its items, abandoning the user's session
+// in progress if there is one.
+func (r *ReviewSessionRepository) Create(ctx context.Context, session *models.ReviewSession) error {
+	session.ID = uuid.New()
+	session.Status = models.ReviewSessionStatusActive
+	session.CreatedAt = time.Now().UTC()
+	if session.Tags == nil {
+		session.Tags = []string{}
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin review session transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	abandonQuery := `
+		UPDATE review_sessions
+		SET status = 'abandoned', completed_at = NOW()
+		WHERE user_id = $1 AND status = 'active'
+	`
+
+	if _, err := tx.ExecContext(ctx, abandonQuery, session.UserID); err != nil {
+		return fmt.Errorf("failed to abandon review session: %w", err)
+	}
+
+	sessionQuery := `
+		INSERT INTO review_sessions (id, user_id, status, tags, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err = tx.ExecContext(ctx, sessionQuery, session.ID, session.UserID, session.Status, pq.Array(session.Tags), session.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create review session: %w", err)
+	}
+
+	itemQuery := `
+		INSERT INTO review_session_items (id, session_id, position, content_id, quiz_type)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	for _, item := range session.Items {
+		item.ID = uuid.New()
+		item.SessionID = session.ID
+		_, err = tx.ExecContext(ctx, itemQuery, item.ID, item.SessionID, item.Position, item.ContentID, item.QuizType)
+		if err != nil {
+			return fmt.Errorf("failed to create review session item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit review session: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID returns a session with its items, or nil if it doesn't exist for
+// the user.
+func (r *ReviewSessionRepository) GetByID(ctx context.Context, userID, sessionID uuid.UUID) (*models.ReviewSession, error) {
+	query := `
+		SELECT ` + reviewSessionColumns + `
+		FROM review_sessions
+		WHERE user_id = $1 AND id = $2
+	`
+	return r.getSession(ctx, query, userID, sessionID)
+}
+
+// GetActive returns the user's session in progress, or nil if there is none.
+func (r *ReviewSessionRepository) GetActive(ctx context.Context, userID uuid.UUID) (*models.ReviewSession, error) {
+	query := `
+		SELECT ` + reviewSessionColumns + `
+		FROM review_sessions
+		WHERE user_id = $1 AND status = 'active'
+	`
+	return r.getSession(ctx, query, userID)
+}
+
+func (r *ReviewSessionRepository) getSession(ctx context.Context, query string, args ...interface{}) (*models.ReviewSession, error) {
+	var session models.ReviewSession
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.Status,
+		pq.Array(&session.Tags),
+		&session.CreatedAt,
+		&session.CompletedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review session: %w", err)
+	}
+
+	itemsQuery := `
+		SELECT ` + reviewItemColumns + `
+		FROM review_session_items
+		WHERE session_id = $1
+		ORDER BY position
+	`
+
+	rows, err := r.db.QueryContext(ctx, itemsQuery, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review session items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanReviewItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan review session item: %w", err)
+		}
+		session.Items = append(session.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get review session items: %w", err)
+	}
+
+	return &session, nil
+}
+
+// SetQuestion stores the generated question of an item unless one was
+// stored first, and returns the item as stored. The item takes the quiz's
+// type, which may differ from the planned one.
+func (r *ReviewSessionRepository) SetQuestion(ctx context.Context, itemID uuid.UUID, quiz *models.GeneratedQuiz) (*models.ReviewSessionItem, error) {
+	options, terms := quiz.Options, quiz.Terms
+	if options == nil {
+		options = []string{}
+	}
+	if terms == nil {
+		terms = []string{}
+	}
+
+	query := `
+		UPDATE review_session_items
+		SET quiz_type = $2, question = $3, options = $4, terms = $5, correct_answer = $6
+		WHERE id = $1 AND question IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, itemID, quiz.QuizType, quiz.Question, pq.Array(options), pq.Array(terms), quiz.CorrectAnswer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set review question: %w", err)
+	}
+
+	itemQuery := `
+		SELECT ` + reviewItemColumns + `
+		FROM review_session_items
+		WHERE id = $1
+	`
+
+	item, err := scanReviewItem(r.db.QueryRowContext(ctx, itemQuery, itemID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review session item: %w", err)
+	}
+
+	return item, nil
+}
+
+// ClaimAnswer records the user's answer to an item before it is graded.
+// Returns false if the item was already answered, so an answer is only
+// graded once.
+func (r *ReviewSessionRepository) ClaimAnswer(ctx context.Context, item *models.ReviewSessionItem) (bool, error) {
+	query := `
+		UPDATE review_session_items
+		SET user_answer = $2, answered_at = $3
+		WHERE id = $1 AND answered_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, item.ID, item.UserAnswer, item.AnsweredAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to record review answer: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to record review answer: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// ReleaseAnswer undoes ClaimAnswer for an answer that couldn't be graded, so
+// the item can be answered again.
+func (r *ReviewSessionRepository) ReleaseAnswer(ctx context.Context, itemID uuid.UUID) error {
+	query := `
+		UPDATE review_session_items
+		SET user_answer = NULL, answered_at = NULL
+		WHERE id = $1 AND quiz_log_id IS NULL
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, itemID); err != nil {
+		return fmt.Errorf("failed to release review answer: %w", err)
+	}
+
+	return nil
+}
+
+// SetAnswerResult stores the grade, quiz log and mastery change of an
+// answered item.
+func (r *ReviewSessionRepository) SetAnswerResult(ctx context.Context, item *models.ReviewSessionItem) error {
+	rubricJSON, err := marshalRubric(item.Rubric)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE review_session_items
+		SET is_correct = $2, grade = $3, feedback = $4, rubric = $5,
+			quiz_log_id = $6, mastery_before = $7, mastery_after = $8, next_review_date = $9
+		WHERE id = $1
+	`
+
+	_, err = r.db.ExecContext(ctx, query, item.ID, item.IsCorrect, item.Grade, item.Feedback, rubricJSON,
+		item.QuizLogID, item.MasteryBefore, item.MasteryAfter, item.NextReviewDate)
+	if err != nil {
+		return fmt.Errorf("failed to set review answer result: %w", err)
+	}
+
+	return nil
+}
+
+// Complete marks an active session as completed. Returns false if it
+// wasn't active.
+func (r *ReviewSessionRepository) Complete(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	query := `
+		UPDATE review_sessions
+		SET status = 'completed', completed_at = NOW()
+		WHERE id = $1 AND status = 'active'
+	`
+
+	result, err := r.db.ExecContext(ctx, query, sessionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to complete review session: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to complete review session: %w", err)
+	}
+
+	return affected > 0, nil
+}
@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"lexipath-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type TranslationRepository struct {
+	db *sql.DB
+}
+
+func NewTranslationRepository(db *sql.DB) *TranslationRepository {
+	return &TranslationRepository{db: db}
+}
+
+const translationColumns = `id, user_id, source_text, normalized_text, base_lang, target_lang, translation, lookup_count, content_id, created_at, last_looked_up_at`
+
+func scanTranslation(row rowScanner) (*models.TranslationMemory, error) {
+	var entry models.TranslationMemory
+	err := row.Scan(
+		&entry.ID,
+		&entry.UserID,
+		&entry.SourceText,
+		&entry.NormalizedText,
+		&entry.BaseLang,
+		&entry.TargetLang,
+		&entry.Translation,
+		&entry.LookupCount,
+		&entry.ContentID,
+		&entry.CreatedAt,
+		&entry.LastLookedUpAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// RecordLookup stores a translation in the user's memory, or bumps the lookup
+// count of an existing entry for the same normalized text and language pair.
+func (r *TranslationRepository) RecordLookup(ctx context.Context, userID uuid.UUID, sourceText, normalizedText, baseLang, targetLang, translation string) (*models.TranslationMemory, error) {
+	now := time.Now().UTC()
+
+	query := `
+		INSERT INTO translation_memory (id, user_id, source_text, normalized_text, base_lang, target_lang, translation, lookup_count, created_at, last_looked_up_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, 1, $8, $8)
+		ON CONFLICT (user_id, base_lang, target_lang, normalized_text)
+		DO UPDATE SET
+			translation = EXCLUDED.translation,
+			lookup_count = translation_memory.lookup_count + 1,
+			last_looked_up_at = EXCLUDED.last_looked_up_at
+		RETURNING ` + translationColumns
+
+	entry, err := scanTranslation(r.db.QueryRowContext(ctx, query,
+		uuid.New(),
+		userID,
+		sourceText,
+		normalizedText,
+		baseLang,
+		targetLang,
+		translation,
+		now,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record translation lookup: %w", err)
+	}
+
+	return entry, nil
+}
+
+func (r *TranslationRepository) GetByID(ctx context.Context, userID, id uuid.UUID) (*models.TranslationMemory, error) {
+	query := `
+		SELECT ` + translationColumns + `
+		FROM translation_memory
+		WHERE user_id = $1 AND id = $2
+	`
+
+	entry, err := scanTranslation(r.db.QueryRowContext(ctx, query, userID, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get translation: %w", err)
+	}
+
+	return entry, nil
+}
+
+func (r *TranslationRepository) ListByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.TranslationMemory, error) {
+	query := `
+		SELECT ` + translationColumns + `
+		FROM translation_memory
+		WHERE user_id = $1
+		ORDER BY last_looked_up_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list translations: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.TranslationMemory
+	for rows.Next() {
+		entry, err := scanTranslation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan translation row: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (r *TranslationRepository) SetContentID(ctx context.Context, id, contentID uuid.UUID) error {
+	query := `UPDATE translation_memory SET content_id = $2 WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id, contentID); err != nil {
+		return fmt.Errorf("failed to link translation to content: %w", err)
+	}
+
+	return nil
+}
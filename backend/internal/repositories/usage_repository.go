@@ -0,0 +1,225 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"lexipath-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type UsageRepository struct {
+	db *sql.DB
+}
+
+func NewUsageRepository(db *sql.DB) *UsageRepository {
+	return &UsageRepository{db: db}
+}
+
+func (r *UsageRepository) Create(ctx context.Context, usage *models.LLMUsage) error {
+	usage.ID = uuid.New()
+	usage.CreatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO llm_usage (id, user_id, feature, model, prompt_tokens, candidates_tokens, total_tokens, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		usage.ID,
+		usage.UserID,
+		usage.Feature,
+		usage.Model,
+		usage.PromptTokens,
+		usage.CandidatesTokens,
+		usage.TotalTokens,
+		usage.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to record llm usage: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserTokenTotals returns the tokens a user has consumed since the start
+// of the given day and since the start of that day's month.
+func (r *UsageRepository) GetUserTokenTotals(ctx context.Context, userID uuid.UUID, dayStart, monthStart time.Time) (int, int, error) {
+	query := `
+		SELECT
+			COALESCE(SUM(total_tokens) FILTER (WHERE created_at >= $2), 0) AS daily_tokens,
+			COALESCE(SUM(total_tokens), 0) AS monthly_tokens
+		FROM llm_usage
+		WHERE user_id = $1 AND created_at >= $3
+	`
+
+	var daily, monthly int
+	if err := r.db.QueryRowContext(ctx, query, userID, dayStart, monthStart).Scan(&daily, &monthly); err != nil {
+		return 0, 0, fmt.Errorf("failed to get user token totals: %w", err)
+	}
+
+	return daily, monthly, nil
+}
+
+// GetBudgetForUser resolves the budget that applies to a user: a per-user
+// override if one exists, otherwise the budget of the user's tier.
+func (r *UsageRepository) GetBudgetForUser(ctx context.Context, userID uuid.UUID) (*models.UsageBudget, error) {
+	query := `
+		SELECT b.id, b.tier, b.user_id, b.daily_token_limit, b.monthly_token_limit, b.created_at, b.updated_at
+		FROM users u
+		JOIN usage_budgets b ON b.user_id = u.id OR b.tier = u.tier
+		WHERE u.id = $1
+		ORDER BY b.user_id IS NULL
+		LIMIT 1
+	`
+
+	var budget models.UsageBudget
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+		&budget.ID,
+		&budget.Tier,
+		&budget.UserID,
+		&budget.DailyTokenLimit,
+		&budget.MonthlyTokenLimit,
+		&budget.CreatedAt,
+		&budget.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage budget: %w", err)
+	}
+
+	return &budget, nil
+}
+
+func (r *UsageRepository) UpsertBudget(ctx context.Context, req *models.SetUsageBudgetRequest) (*models.UsageBudget, error) {
+	now := time.Now().UTC()
+	budget := &models.UsageBudget{
+		ID:                uuid.New(),
+		Tier:              req.Tier,
+		UserID:            req.UserID,
+		DailyTokenLimit:   req.DailyTokenLimit,
+		MonthlyTokenLimit: req.MonthlyTokenLimit,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+
+	conflictTarget := "tier"
+	if req.UserID != nil {
+		conflictTarget = "user_id"
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO usage_budgets (id, tier, user_id, daily_token_limit, monthly_token_limit, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (%s)
+		DO UPDATE SET
+			daily_token_limit = EXCLUDED.daily_token_limit,
+			monthly_token_limit = EXCLUDED.monthly_token_limit,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at
+	`, conflictTarget)
+
+	err := r.db.QueryRowContext(ctx, query,
+		budget.ID,
+		budget.Tier,
+		budget.UserID,
+		budget.DailyTokenLimit,
+		budget.MonthlyTokenLimit,
+		budget.CreatedAt,
+		budget.UpdatedAt,
+	).Scan(&budget.ID, &budget.CreatedAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert usage budget: %w", err)
+	}
+
+	return budget, nil
+}
+
+// usageGroupColumns maps the supported summary dimensions to ledger columns.
+var usageGroupColumns = map[string]string{
+	"user":    "user_id",
+	"feature": "feature",
+	"model":   "model",
+}
+
+// GetSummary aggregates the ledger between from (inclusive) and to
+// (exclusive), grouped by any combination of user, feature and model.
+func (r *UsageRepository) GetSummary(ctx context.Context, from, to time.Time, groupBy []string) ([]*models.UsageSummary, error) {
+	var columns []string
+	for _, dimension := range groupBy {
+		column, ok := usageGroupColumns[dimension]
+		if !ok {
+			return nil, fmt.Errorf("unsupported usage grouping %q", dimension)
+		}
+		columns = append(columns, column)
+	}
+
+	selectColumns := "NULL::uuid, NULL::text, NULL::text"
+	groupClause := ""
+	if len(columns) > 0 {
+		selectColumns = fmt.Sprintf("%s, %s, %s",
+			pickColumn(columns, "user_id", "NULL::uuid"),
+			pickColumn(columns, "feature", "NULL::text"),
+			pickColumn(columns, "model", "NULL::text"))
+		groupClause = "GROUP BY " + strings.Join(columns, ", ")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s,
+			COUNT(*) AS calls,
+			COALESCE(SUM(prompt_tokens), 0),
+			COALESCE(SUM(candidates_tokens), 0),
+			COALESCE(SUM(total_tokens), 0) AS total
+		FROM llm_usage
+		WHERE created_at >= $1 AND created_at < $2
+		%s
+		ORDER BY total DESC
+	`, selectColumns, groupClause)
+
+	rows, err := r.db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage summary: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []*models.UsageSummary
+	for rows.Next() {
+		var summary models.UsageSummary
+		var feature, model sql.NullString
+		err := rows.Scan(
+			&summary.UserID,
+			&feature,
+			&model,
+			&summary.Calls,
+			&summary.PromptTokens,
+			&summary.CandidatesTokens,
+			&summary.TotalTokens,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan usage summary row: %w", err)
+		}
+		summary.Feature = feature.String
+		summary.Model = model.String
+		summaries = append(summaries, &summary)
+	}
+
+	return summaries, nil
+}
+
+func pickColumn(columns []string, column, fallback string) string {
+	for _, c := range columns {
+		if c == column {
+			return column
+		}
+	}
+	return fallback
+}
@@ -21,7 +21,7 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 
 func (r *UserRepository) GetByFirebaseUID(ctx context.Context, firebaseUID string) (*models.User, error) {
 	query := `
-		SELECT id, firebase_uid, email, created_at, updated_at
+		SELECT id, firebase_uid, email, tier, created_at, updated_at
 		FROM users
 		WHERE firebase_uid = $1
 	`
@@ -31,6 +31,7 @@ func (r *UserRepository) GetByFirebaseUID(ctx context.Context, firebaseUID strin
 		&user.ID,
 		&user.FirebaseUID,
 		&user.Email,
+		&user.Tier,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -45,24 +46,53 @@ func (r *UserRepository) GetByFirebaseUID(ctx context.Context, firebaseUID strin
 	return &user, nil
 }
 
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	query := `
+		SELECT id, firebase_uid, email, tier, created_at, updated_at
+		FROM users
+		WHERE LOWER(email) = LOWER($1)
+	`
+
+	var user models.User
+	err := r.db.QueryRowContext(ctx, query, email).Scan(
+		&user.ID,
+		&user.FirebaseUID,
+		&user.Email,
+		&user.Tier,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
+	}
+
+	return &user, nil
+}
+
 func (r *UserRepository) Create(ctx context.Context, firebaseUID, email string) (*models.User, error) {
 	user := &models.User{
 		ID:          uuid.New(),
 		FirebaseUID: firebaseUID,
 		Email:       email,
+		Tier:        "free",
 		CreatedAt:   time.Now().UTC(),
 		UpdatedAt:   time.Now().UTC(),
 	}
 
 	query := `
-		INSERT INTO users (id, firebase_uid, email, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO users (id, firebase_uid, email, tier, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
 		user.ID,
 		user.FirebaseUID,
 		user.Email,
+		user.Tier,
 		user.CreatedAt,
 		user.UpdatedAt,
 	)
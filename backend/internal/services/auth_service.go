@@ -49,10 +49,15 @@ func (s *AuthService) VerifyToken(ctx context.Context, idToken string) (*models.
 	}
 
 	// Get or create user
-	user, err := s.userRepo.GetOrCreate(ctx, token.UID, token.Claims["email"].(string))
+	email, _ := token.Claims["email"].(string)
+	user, err := s.userRepo.GetOrCreate(ctx, token.UID, email)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get or create user: %w", err)
 	}
 
+	if verified, _ := token.Claims["email_verified"].(bool); verified {
+		user.VerifiedEmail = email
+	}
+
 	return user, nil
 }
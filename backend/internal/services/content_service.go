@@ -2,114 +2,963 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"math/rand"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"lexipath-backend/internal/models"
 	"lexipath-backend/internal/repositories"
+	"lexipath-backend/internal/wordlists"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+const (
+	// maxExcludedWords caps how many recently seen words go into the prompt.
+	maxExcludedWords = 100
+	// maxDuplicateRegenerations bounds Gemini calls spent on avoiding repeats.
+	maxDuplicateRegenerations = 3
+	// maxDailySkips is how many daily words a user can skip per date.
+	maxDailySkips = 3
+	// maxNoteLength, maxTagLength and maxContentTags bound user annotations, in characters.
+	maxNoteLength  = 2000
+	maxTagLength   = 40
+	maxContentTags = 20
+	// maxSearchQueryLength caps search queries, in characters.
+	maxSearchQueryLength = 200
+	// maxStudyWordLength is the longest word a study item can hold, in characters.
+	maxStudyWordLength = 255
+	// maxSkipFeedbackWords caps how many skipped words per reason go into the prompt.
+	maxSkipFeedbackWords = 20
+	// knownMasteryScore is seeded for words skipped as known.
+	knownMasteryScore = 90
+	// knownReviewInterval is when a word skipped as known is next reviewed.
+	knownReviewInterval = 30 * 24 * time.Hour
+)
+
+var (
+	// ErrContentNotFound is returned when a content item doesn't exist for the user.
+	ErrContentNotFound = errors.New("content not found")
+	// ErrInvalidSkip is returned for a skip with an unknown reason or of an item that isn't a daily word.
+	ErrInvalidSkip = errors.New("invalid skip")
+	// ErrSkipLimitReached is returned once the user has skipped maxDailySkips words for the date.
+	ErrSkipLimitReached = errors.New("skip limit reached")
+	// ErrInvalidSearch is returned for an empty or overlong search query.
+	ErrInvalidSearch = errors.New("invalid search")
+	// ErrInvalidAnnotation is returned for a note or tags that can't be saved.
+	ErrInvalidAnnotation = errors.New("invalid annotation")
+	// ErrInvalidCursor is returned for a pagination cursor that wasn't issued by ListContent.
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrInvalidPromotion is returned for a translation too long to study as a word.
+	ErrInvalidPromotion = errors.New("invalid promotion")
+)
+
 type ContentService struct {
-	contentRepo   *repositories.ContentRepository
-	cacheRepo     *repositories.CacheRepository
-	geminiService *GeminiService
-	logger        *zap.Logger
+	contentRepo     *repositories.ContentRepository
+	translationRepo *repositories.TranslationRepository
+	lemmaRepo       *repositories.LemmaRepository
+	masteryRepo     *repositories.MasteryRepository
+	cacheRepo       *repositories.CacheRepository
+	lexiconService  *LexiconService
+	geminiService   *GeminiService
+	guard           *PromptGuard
+	location        *time.Location
+	logger          *zap.Logger
 }
 
-func NewContentService(contentRepo *repositories.ContentRepository, cacheRepo *repositories.CacheRepository, geminiService *GeminiService, logger *zap.Logger) *ContentService {
+func NewContentService(contentRepo *repositories.ContentRepository, translationRepo *repositories.TranslationRepository, lemmaRepo *repositories.LemmaRepository, masteryRepo *repositories.MasteryRepository, cacheRepo *repositories.CacheRepository, lexiconService *LexiconService, geminiService *GeminiService, guard *PromptGuard, location *time.Location, logger *zap.Logger) *ContentService {
 	return &ContentService{
-		contentRepo:   contentRepo,
-		cacheRepo:     cacheRepo,
-		geminiService: geminiService,
-		logger:        logger,
+		contentRepo:     contentRepo,
+		translationRepo: translationRepo,
+		lemmaRepo:       lemmaRepo,
+		masteryRepo:     masteryRepo,
+		cacheRepo:       cacheRepo,
+		lexiconService:  lexiconService,
+		geminiService:   geminiService,
+		guard:           guard,
+		location:        location,
+		logger:          logger,
 	}
 }
 
-func (s *ContentService) GetDailyContent(ctx context.Context, userID uuid.UUID, profile *models.Profile, date time.Time) (*models.DailyContent, error) {
-	// Check rate limit
-	count, err := s.cacheRepo.GetRateLimit(ctx, userID, "daily-content")
-	if err != nil {
-		s.logger.Warn("Failed to check rate limit", zap.Error(err))
-	} else if count >= 10 { // Max 10 requests per day
-		return nil, fmt.Errorf("daily rate limit exceeded")
+// dailyWordCount is how many words the profile gets per day.
+func dailyWordCount(profile *models.Profile) int {
+	if profile.DailyWordCount < models.MinDailyWordCount {
+		return models.MinDailyWordCount
 	}
+	return profile.DailyWordCount
+}
+
+func (s *ContentService) GetDailyContent(ctx context.Context, userID uuid.UUID, profile *models.Profile, date time.Time) (*models.DailyContentSet, error) {
+	count := dailyWordCount(profile)
 
-	// Try cache first
-	content, err := s.cacheRepo.GetDailyContent(ctx, userID, date)
+	// Try cache first. A cached set may be short if the word count was raised since.
+	set, err := s.cacheRepo.GetDailyContent(ctx, userID, date)
 	if err != nil {
 		s.logger.Warn("Failed to get cached content", zap.Error(err))
 	}
-	if content != nil {
-		return content, nil
+	if set != nil && len(set.Items) >= count {
+		return set, nil
 	}
 
 	// Try database
-	content, err = s.contentRepo.GetByUserAndDate(ctx, userID, date)
+	items, err := s.contentRepo.ListByUserAndDate(ctx, userID, date)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get content from database: %w", err)
 	}
-	if content != nil {
-		// Cache the result
-		if err := s.cacheRepo.SetDailyContent(ctx, content); err != nil {
+
+	if missing := count - len(items); missing > 0 {
+		created, err := s.createDailyContent(ctx, profile, date, nextPosition(items), missing)
+		items = append(items, created...)
+		if err != nil {
+			if len(items) > 0 {
+				// Serve what we have; the set isn't cached, so the next request tops it up
+				s.logger.Warn("Serving partial daily content", zap.Error(err), zap.String("user_id", userID.String()), zap.Int("count", len(items)))
+				return &models.DailyContentSet{Date: date, Items: items}, nil
+			}
+
+			// Budget and input errors are the user's to resolve, anything else
+			// is most likely Gemini being unavailable
+			if errors.Is(err, ErrUsageBudgetExceeded) || errors.Is(err, ErrInputRejected) {
+				return nil, err
+			}
+
+			fallback, fallbackErr := s.fallbackContent(ctx, profile, date)
+			if fallbackErr != nil {
+				s.logger.Warn("Failed to get fallback content", zap.Error(fallbackErr))
+			}
+			if fallback == nil {
+				return nil, err
+			}
+
+			// Not cached, so the next request retries generation
+			s.logger.Warn("Serving fallback daily content", zap.Error(err), zap.String("user_id", userID.String()))
+			return &models.DailyContentSet{Date: date, Items: []*models.DailyContent{fallback}}, nil
+		}
+	}
+
+	set = &models.DailyContentSet{Date: date, Items: items}
+	if len(items) >= count {
+		if err := s.cacheRepo.SetDailyContent(ctx, userID, set); err != nil {
 			s.logger.Warn("Failed to cache content", zap.Error(err))
 		}
+	}
+
+	return set, nil
+}
+
+// HasDailyContent reports whether the profile's full set of words for the
+// date already exists.
+func (s *ContentService) HasDailyContent(ctx context.Context, profile *models.Profile, date time.Time) (bool, error) {
+	items, err := s.contentRepo.ListByUserAndDate(ctx, profile.UserID, date)
+	if err != nil {
+		return false, fmt.Errorf("failed to get content from database: %w", err)
+	}
+	return len(items) >= dailyWordCount(profile), nil
+}
+
+// PregenerateDailyContent creates and caches the daily content for a date
+// ahead of time. It reports whether anything was generated; words that
+// already exist are left alone.
+func (s *ContentService) PregenerateDailyContent(ctx context.Context, profile *models.Profile, date time.Time) (bool, error) {
+	count := dailyWordCount(profile)
+
+	items, err := s.contentRepo.ListByUserAndDate(ctx, profile.UserID, date)
+	if err != nil {
+		return false, fmt.Errorf("failed to get content from database: %w", err)
+	}
+	if len(items) >= count {
+		return false, nil
+	}
+
+	created, err := s.createDailyContent(ctx, profile, date, nextPosition(items), count-len(items))
+	if err != nil {
+		return len(created) > 0, err
+	}
+
+	set := &models.DailyContentSet{Date: date, Items: append(items, created...)}
+	if err := s.cacheRepo.SetDailyContent(ctx, profile.UserID, set); err != nil {
+		s.logger.Warn("Failed to cache pregenerated content", zap.Error(err))
+	}
+
+	return true, nil
+}
+
+// today returns the profile's current local date, in its timezone if it has
+// a valid one and the server's otherwise.
+func (s *ContentService) today(profile *models.Profile) time.Time {
+	return localNow(profile, s.location)
+}
+
+// localNow returns the current time in the profile's timezone if it has a
+// valid one, and in the fallback location otherwise.
+func localNow(profile *models.Profile, fallback *time.Location) time.Time {
+	location := fallback
+	if profile.Timezone != nil && *profile.Timezone != "" {
+		if loc, err := time.LoadLocation(*profile.Timezone); err == nil {
+			location = loc
+		}
+	}
+	return time.Now().In(location)
+}
+
+// SkipDailyContent removes a word from the user's set for today and tops the
+// set back up with a replacement. The word stays in the user's lemma
+// index so it isn't served again, and the reason feeds back into selection:
+// known and too-hard skips steer the difficulty of generated words, and
+// inappropriate reports count against the word bank entry.
+func (s *ContentService) SkipDailyContent(ctx context.Context, profile *models.Profile, req *models.SkipDailyContentRequest) (*models.DailyContentSet, error) {
+	switch req.Reason {
+	case models.SkipReasonKnown, models.SkipReasonTooHard, models.SkipReasonInappropriate, models.SkipReasonNotRelevant:
+	default:
+		return nil, fmt.Errorf("%w: unknown reason %q", ErrInvalidSkip, req.Reason)
+	}
+
+	userID := profile.UserID
+	content, err := s.contentRepo.GetByID(ctx, userID, req.ContentID)
+	if err != nil {
+		return nil, err
+	}
+	if content == nil {
+		return nil, ErrContentNotFound
+	}
+	if content.Source != models.ContentSourceDaily {
+		return nil, fmt.Errorf("%w: only daily words can be skipped", ErrInvalidSkip)
+	}
+	// A replacement for a past date would never be studied
+	if content.Date.Format("2006-01-02") != s.today(profile).Format("2006-01-02") {
+		return nil, fmt.Errorf("%w: only today's words can be skipped", ErrInvalidSkip)
+	}
+
+	if content.SkippedAt == nil {
+		skipped, limitReached, err := s.contentRepo.MarkSkipped(ctx, userID, content.ID, content.Date, req.Reason, maxDailySkips)
+		if err != nil {
+			return nil, err
+		}
+		if limitReached {
+			return nil, ErrSkipLimitReached
+		}
+
+		// A concurrent skip of the same word is fine, the set is returned either way
+		if skipped {
+			if err := s.cacheRepo.DeleteDailyContent(ctx, userID, content.Date); err != nil {
+				s.logger.Warn("Failed to invalidate cached content", zap.Error(err))
+			}
+
+			s.applySkipFeedback(ctx, content, req)
+		}
+	}
+
+	return s.GetDailyContent(ctx, userID, profile, content.Date)
+}
+
+func (s *ContentService) applySkipFeedback(ctx context.Context, content *models.DailyContent, req *models.SkipDailyContentRequest) {
+	switch req.Reason {
+	case models.SkipReasonKnown:
+		if !req.SeedMastery {
+			return
+		}
+		nextReview := time.Now().Add(knownReviewInterval)
+		if _, err := s.masteryRepo.Upsert(ctx, content.UserID, content.ID, knownMasteryScore, &nextReview); err != nil {
+			s.logger.Warn("Failed to seed mastery for known word", zap.Error(err))
+		}
+	case models.SkipReasonInappropriate:
+		if content.LexiconEntryID == nil {
+			return
+		}
+		if err := s.lexiconService.ReportInappropriate(ctx, *content.LexiconEntryID); err != nil {
+			s.logger.Warn("Failed to report lexicon entry", zap.Error(err))
+		}
+	}
+}
+
+// nextPosition returns the position after the last item of a day's set.
+func nextPosition(items []*models.DailyContent) int {
+	if len(items) == 0 {
+		return 0
+	}
+	return items[len(items)-1].Position + 1
+}
+
+// createDailyContent stores count new words for the date starting at the
+// given position. Words are drawn from the shared word bank while it has
+// unseen entries for the user, and the rest are generated in one batch.
+// Words stored before an error are returned along with it.
+func (s *ContentService) createDailyContent(ctx context.Context, profile *models.Profile, date time.Time, position, count int) ([]*models.DailyContent, error) {
+	scope := vocabularyScope(profile)
+	var created []*models.DailyContent
+
+	drawn := make(map[uuid.UUID]bool)
+	for len(created) < count {
+		entry, err := s.lexiconService.DrawUnseen(ctx, profile)
+		if err != nil {
+			s.logger.Warn("Failed to draw from word bank", zap.Error(err))
+			break
+		}
+		// A repeat means the lemma index didn't record the last draw
+		if entry == nil || drawn[entry.ID] {
+			break
+		}
+		drawn[entry.ID] = true
+
+		content := &models.DailyContent{
+			Word:           entry.Word,
+			Meaning:        entry.Meaning,
+			ExamplesTarget: entry.ExamplesTarget,
+			ExamplesBase:   entry.ExamplesBase,
+			LexicalInfo:    entry.LexicalInfo,
+			LexiconEntryID: &entry.ID,
+		}
+		if err := s.storeDailyContent(ctx, profile, date, position+len(created), scope, entry.Lemma, content); err != nil {
+			return created, err
+		}
+		created = append(created, content)
+	}
+
+	missing := count - len(created)
+	if missing == 0 {
+		return created, nil
+	}
+
+	// Word bank exhausted for this user, generate the rest using Gemini
+	s.logger.Info("Generating new daily content",
+		zap.String("user_id", profile.UserID.String()),
+		zap.Time("date", date),
+		zap.Int("count", missing))
+
+	words, err := s.generateNewWords(ctx, profile, scope, missing)
+	for _, word := range words {
+		content := &models.DailyContent{
+			Word:           word.resp.Word,
+			Meaning:        word.resp.Meaning,
+			ExamplesTarget: word.resp.ExamplesTarget,
+			ExamplesBase:   word.resp.ExamplesBase,
+			LexicalInfo:    word.resp.LexicalInfo,
+		}
+
+		generated, addErr := s.lexiconService.AddGenerated(ctx, profile, word.lemma, word.resp)
+		if addErr != nil {
+			s.logger.Warn("Failed to add generated word to word bank", zap.Error(addErr))
+		} else {
+			content.LexiconEntryID = &generated.ID
+		}
+
+		if storeErr := s.storeDailyContent(ctx, profile, date, position+len(created), scope, word.lemma, content); storeErr != nil {
+			return created, storeErr
+		}
+		created = append(created, content)
+	}
+	if err != nil {
+		return created, err
+	}
+	if len(created) < count {
+		return created, fmt.Errorf("generated %d of %d new words", len(created), count)
+	}
+
+	return created, nil
+}
+
+func (s *ContentService) storeDailyContent(ctx context.Context, profile *models.Profile, date time.Time, position int, scope, lemma string, content *models.DailyContent) error {
+	content.UserID = profile.UserID
+	content.Date = date
+	content.Position = position
+	content.Source = models.ContentSourceDaily
+	if err := s.contentRepo.Insert(ctx, content); err != nil {
+		return fmt.Errorf("failed to save content: %w", err)
+	}
+
+	if err := s.lemmaRepo.Add(ctx, profile.UserID, scope, lemma, &content.ID); err != nil {
+		s.logger.Warn("Failed to index lemma", zap.Error(err), zap.String("lemma", lemma))
+	}
+
+	return nil
+}
+
+// generatedWord is a generated word with its normalized lemma.
+type generatedWord struct {
+	resp  *models.GeminiDailyContentResponse
+	lemma string
+}
+
+// generateNewWords asks Gemini for up to count words the user hasn't been
+// taught yet in this scope. Recently seen words are passed as exclusions, and
+// duplicates that slip through are added to the exclusions and regenerated.
+func (s *ContentService) generateNewWords(ctx context.Context, profile *models.Profile, scope string, count int) ([]generatedWord, error) {
+	exclude, err := s.lemmaRepo.ListRecent(ctx, profile.UserID, scope, maxExcludedWords)
+	if err != nil {
+		s.logger.Warn("Failed to list recent lemmas", zap.Error(err))
+	}
+
+	skipped := make(map[models.SkipReason][]string)
+	for _, reason := range []models.SkipReason{models.SkipReasonKnown, models.SkipReasonTooHard} {
+		lemmas, err := s.contentRepo.ListSkippedLemmas(ctx, profile.UserID, scope, reason, maxSkipFeedbackWords)
+		if err != nil {
+			s.logger.Warn("Failed to list skipped lemmas", zap.Error(err))
+		}
+		skipped[reason] = lemmas
+	}
+
+	var words []generatedWord
+	inBatch := make(map[string]bool)
+	for attempt := 0; attempt < maxDuplicateRegenerations && len(words) < count; attempt++ {
+		resps, err := s.geminiService.GenerateDailyContent(ctx, profile, exclude, skipped, count-len(words))
+		if err != nil {
+			return words, fmt.Errorf("failed to generate content: %w", err)
+		}
+
+		for i := range resps {
+			if len(words) == count {
+				break
+			}
+
+			lemma := normalizeLemma(resps[i].Word, scope)
+			if inBatch[lemma] {
+				continue
+			}
+			seen, err := s.lemmaRepo.Exists(ctx, profile.UserID, scope, lemma)
+			if err != nil {
+				return words, err
+			}
+			inBatch[lemma] = true
+			exclude = append(exclude, lemma)
+
+			if seen {
+				s.logger.Info("Generated word was already seen, regenerating",
+					zap.String("user_id", profile.UserID.String()),
+					zap.String("lemma", lemma),
+					zap.Int("attempt", attempt+1))
+				continue
+			}
+			words = append(words, generatedWord{resp: &resps[i], lemma: lemma})
+		}
+	}
+
+	if len(words) == 0 {
+		return nil, fmt.Errorf("failed to generate a new word after %d attempts", maxDuplicateRegenerations)
+	}
+
+	return words, nil
+}
+
+// NormalizeLemmas strips leading articles from lemmas that were indexed
+// with them, which the migration that backfilled the index from existing
+// content can't do. It returns how many lemmas were changed.
+func (s *ContentService) NormalizeLemmas(ctx context.Context) (int, error) {
+	var changed int
+	for language, articles := range leadingArticles {
+		words, err := s.lemmaRepo.ListLeadingArticle(ctx, language, articles)
+		if err != nil {
+			return changed, err
+		}
+		for id, word := range words {
+			renamed, err := s.lemmaRepo.Rename(ctx, id, normalizeLemma(word, language))
+			if err != nil {
+				return changed, err
+			}
+			if renamed {
+				changed++
+			}
+		}
+	}
+	return changed, nil
+}
+
+// fallbackContent returns something to study when the daily word can't be
+// created: an unseen word from the bundled word list, stored as the day's
+// fallback item, or else an item that is due for review. Returns nil if
+// neither is available.
+func (s *ContentService) fallbackContent(ctx context.Context, profile *models.Profile, date time.Time) (*models.DailyContent, error) {
+	existing, err := s.contentRepo.GetByUserDateAndSource(ctx, profile.UserID, date, models.ContentSourceFallback)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	content, err := s.fallbackFromWordList(ctx, profile, date)
+	if err != nil {
+		s.logger.Warn("Failed to draw from bundled word list", zap.Error(err))
+	}
+	if content != nil {
 		return content, nil
 	}
 
-	// Generate new content using Gemini
-	s.logger.Info("Generating new daily content", zap.String("user_id", userID.String()), zap.Time("date", date))
+	return s.fallbackFromReview(ctx, profile.UserID, date)
+}
 
-	// Increment rate limit
-	if _, err := s.cacheRepo.IncrementRateLimit(ctx, userID, "daily-content"); err != nil {
-		s.logger.Warn("Failed to increment rate limit", zap.Error(err))
+func (s *ContentService) fallbackFromWordList(ctx context.Context, profile *models.Profile, date time.Time) (*models.DailyContent, error) {
+	var entries []models.GeminiDailyContentResponse
+	var err error
+	if profile.GoalType == models.GoalTypeLanguage {
+		key := catalogKey(profile)
+		entries, err = wordlists.ForLanguage(key.TargetLang, key.BaseLang, profile.Level)
+	} else {
+		entries, err = wordlists.ForIndustry(profile.Level)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	geminiResp, err := s.geminiService.GenerateDailyContent(ctx, profile)
+	scope := vocabularyScope(profile)
+	for _, i := range rand.Perm(len(entries)) {
+		entry := entries[i]
+		lemma := normalizeLemma(entry.Word, scope)
+
+		seen, err := s.lemmaRepo.Exists(ctx, profile.UserID, scope, lemma)
+		if err != nil {
+			return nil, err
+		}
+		if seen {
+			continue
+		}
+
+		content := &models.DailyContent{
+			UserID:         profile.UserID,
+			Date:           date,
+			Word:           entry.Word,
+			Meaning:        entry.Meaning,
+			ExamplesTarget: entry.ExamplesTarget,
+			ExamplesBase:   entry.ExamplesBase,
+			LexicalInfo:    entry.LexicalInfo,
+			Source:         models.ContentSourceFallback,
+		}
+		if err := s.contentRepo.Insert(ctx, content); err != nil {
+			// A concurrent request may have stored the day's fallback first
+			existing, getErr := s.contentRepo.GetByUserDateAndSource(ctx, profile.UserID, date, models.ContentSourceFallback)
+			if getErr == nil && existing != nil {
+				return existing, nil
+			}
+			return nil, err
+		}
+
+		if err := s.lemmaRepo.Add(ctx, profile.UserID, scope, lemma, &content.ID); err != nil {
+			s.logger.Warn("Failed to index lemma", zap.Error(err), zap.String("lemma", lemma))
+		}
+
+		return content, nil
+	}
+
+	return nil, nil
+}
+
+// fallbackFromReview resurfaces the item most overdue for review. It is not
+// stored again, only marked as fallback in the response.
+func (s *ContentService) fallbackFromReview(ctx context.Context, userID uuid.UUID, date time.Time) (*models.DailyContent, error) {
+	contentIDs, err := s.masteryRepo.GetContentForReview(ctx, userID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, contentID := range contentIDs {
+		content, err := s.contentRepo.GetByID(ctx, userID, contentID)
+		if err != nil {
+			return nil, err
+		}
+		if content != nil {
+			content.Source = models.ContentSourceFallback
+			return content, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ListContent returns a page of the user's content, newest first, with the
+// cursor for the next page, or "" when there are no more items.
+func (s *ContentService) ListContent(ctx context.Context, userID uuid.UUID, filter models.ContentFilter, cursor string, limit int) ([]*models.ContentProgress, string, error) {
+	tags, err := normalizeTags(filter.Tags)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate content: %w", err)
+		return nil, "", err
 	}
+	filter.Tags = tags
 
-	// Save to database
-	content, err = s.contentRepo.Create(ctx, userID, date, geminiResp)
+	var after *models.ContentCursor
+	if cursor != "" {
+		decoded, err := decodeContentCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		after = decoded
+	}
+
+	// One extra item tells whether there is a next page.
+	items, err := s.contentRepo.ListWithProgress(ctx, userID, filter, after, true, limit+1)
 	if err != nil {
-		return nil, fmt.Errorf("failed to save content: %w", err)
+		return nil, "", err
+	}
+
+	if len(items) <= limit {
+		return items, "", nil
+	}
+
+	items = items[:limit]
+	last := items[limit-1]
+	return items, encodeContentCursor(&models.ContentCursor{Date: last.Date, ID: last.ID}), nil
+}
+
+// SearchContent finds the user's content matching the query, best matches
+// first. When full-text search finds nothing, typically because of a typo,
+// it falls back to trigram similarity.
+func (s *ContentService) SearchContent(ctx context.Context, profile *models.Profile, query string, limit int) ([]*models.ContentSearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" || utf8.RuneCountInString(query) > maxSearchQueryLength {
+		return nil, ErrInvalidSearch
 	}
 
-	// Cache the result
-	if err := s.cacheRepo.SetDailyContent(ctx, content); err != nil {
-		s.logger.Warn("Failed to cache new content", zap.Error(err))
+	baseLang := "en"
+	if profile.BaseLang != nil {
+		baseLang = normalizeLang(*profile.BaseLang)
+	}
+
+	results, err := s.contentRepo.Search(ctx, profile.UserID, lexicalLanguage(profile), baseLang, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) > 0 {
+		return results, nil
 	}
 
+	return s.contentRepo.SearchFuzzy(ctx, profile.UserID, query, limit)
+}
+
+// Cursors are opaque to clients: the date and id of the last item, base64
+// encoded.
+func encodeContentCursor(cursor *models.ContentCursor) string {
+	raw := cursor.Date.Format("2006-01-02") + "|" + cursor.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeContentCursor(cursor string) (*models.ContentCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	dateStr, idStr, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &models.ContentCursor{Date: date, ID: id}, nil
+}
+
+// ListDueReviews returns content whose review is due by the date, oldest
+// first, optionally only content carrying all of the tags.
+func (s *ContentService) ListDueReviews(ctx context.Context, userID uuid.UUID, date time.Time, tags []string, limit int) ([]*models.ContentProgress, error) {
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	filter := models.ContentFilter{Tags: tags, DueBy: &date}
+	return s.contentRepo.ListWithProgress(ctx, userID, filter, nil, false, limit)
+}
+
+func (s *ContentService) GetContent(ctx context.Context, userID, contentID uuid.UUID) (*models.DailyContent, error) {
+	content, err := s.contentRepo.GetByID(ctx, userID, contentID)
+	if err != nil {
+		return nil, err
+	}
+	if content == nil {
+		return nil, ErrContentNotFound
+	}
 	return content, nil
 }
 
-func (s *ContentService) GetContentHistory(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.DailyContent, error) {
-	return s.contentRepo.GetHistoryByUser(ctx, userID, limit, offset)
+// UpdateContent changes the user's bookmark, note or tags on a content item
+// and clears the cached daily set of its date.
+func (s *ContentService) UpdateContent(ctx context.Context, userID, contentID uuid.UUID, req *models.UpdateContentRequest) (*models.DailyContent, error) {
+	var note *string
+	if req.Note != nil {
+		trimmed := strings.TrimSpace(*req.Note)
+		if utf8.RuneCountInString(trimmed) > maxNoteLength {
+			return nil, fmt.Errorf("%w: note is longer than %d characters", ErrInvalidAnnotation, maxNoteLength)
+		}
+		note = &trimmed
+	}
+
+	var tags []string
+	if req.Tags != nil {
+		normalized, err := normalizeTags(*req.Tags)
+		if err != nil {
+			return nil, err
+		}
+		// An empty, non-nil slice clears the tags
+		tags = append([]string{}, normalized...)
+	}
+
+	content, err := s.contentRepo.UpdateAnnotations(ctx, userID, contentID, req.Bookmarked, note, tags)
+	if err != nil {
+		return nil, err
+	}
+	if content == nil {
+		return nil, ErrContentNotFound
+	}
+
+	// The cached daily set carries the annotations of the day's words
+	if err := s.cacheRepo.DeleteDailyContent(ctx, userID, content.Date); err != nil {
+		s.logger.Warn("Failed to invalidate cached content", zap.Error(err))
+	}
+
+	return content, nil
+}
+
+// AddContentTags adds tags to a content item, keeping those it has.
+func (s *ContentService) AddContentTags(ctx context.Context, userID, contentID uuid.UUID, tags []string) (*models.DailyContent, error) {
+	content, err := s.GetContent(ctx, userID, contentID)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := append(append([]string{}, content.Tags...), tags...)
+	return s.UpdateContent(ctx, userID, contentID, &models.UpdateContentRequest{Tags: &merged})
+}
+
+// RemoveContentTag removes a tag from a content item. Removing a tag it
+// doesn't have is not an error.
+func (s *ContentService) RemoveContentTag(ctx context.Context, userID, contentID uuid.UUID, tag string) (*models.DailyContent, error) {
+	content, err := s.GetContent(ctx, userID, contentID)
+	if err != nil {
+		return nil, err
+	}
+
+	tag = normalizeTag(tag)
+	remaining := []string{}
+	for _, existing := range content.Tags {
+		if existing != tag {
+			remaining = append(remaining, existing)
+		}
+	}
+	return s.UpdateContent(ctx, userID, contentID, &models.UpdateContentRequest{Tags: &remaining})
+}
+
+func (s *ContentService) ListTags(ctx context.Context, userID uuid.UUID) ([]*models.TagCount, error) {
+	return s.contentRepo.ListTags(ctx, userID)
+}
+
+// normalizeTag lowercases a tag and joins its words with hyphens, so "#Work
+// Trip" and "work-trip" are the same tag.
+func normalizeTag(tag string) string {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	return strings.ToLower(strings.Join(strings.Fields(tag), "-"))
+}
+
+// normalizeTags normalizes and deduplicates tags, dropping empty ones.
+func normalizeTags(tags []string) ([]string, error) {
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength || strings.ContainsAny(tag, ",;") {
+			return nil, fmt.Errorf("%w: invalid tag %q", ErrInvalidAnnotation, tag)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxContentTags {
+		return nil, fmt.Errorf("%w: at most %d tags are allowed", ErrInvalidAnnotation, maxContentTags)
+	}
+	return normalized, nil
 }
 
 func (s *ContentService) Translate(ctx context.Context, userID uuid.UUID, req *models.TranslateRequest) (*models.TranslateResponse, error) {
-	// Check rate limit
-	count, err := s.cacheRepo.GetRateLimit(ctx, userID, "translate")
+	// Sanitize before the cache lookup so that cached lookups are capped too
+	text, err := s.guard.SanitizeText(userID, string(GeminiTaskTranslate), req.Text, maxTranslateTextLength)
+	if err != nil {
+		return nil, err
+	}
+	// Case can change the meaning, so only identical lookups from any user
+	// share one cached translation
+	cacheKey := collapseText(text)
+	baseLang := normalizeLang(req.BaseLang)
+	targetLang := normalizeLang(req.TargetLang)
+
+	geminiResp, err := s.cacheRepo.GetTranslation(ctx, cacheKey, baseLang, targetLang, req.Detail)
+	if err != nil {
+		s.logger.Warn("Failed to get cached translation", zap.Error(err))
+	}
+
+	if geminiResp == nil {
+		// Token budgets are enforced by GeminiService before the call is made
+		if req.Detail {
+			geminiResp, err = s.geminiService.TranslateDetailed(ctx, userID, text, req.TargetLang, req.BaseLang)
+		} else {
+			geminiResp, err = s.geminiService.Translate(ctx, userID, text, req.TargetLang, req.BaseLang)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to translate: %w", err)
+		}
+
+		if err := s.cacheRepo.SetTranslation(ctx, cacheKey, baseLang, targetLang, req.Detail, geminiResp); err != nil {
+			s.logger.Warn("Failed to cache translation", zap.Error(err))
+		}
+	}
+
+	resp := &models.TranslateResponse{
+		Translation:  geminiResp.Translation,
+		Alternatives: geminiResp.Alternatives,
+		Formal:       geminiResp.Formal,
+		Informal:     geminiResp.Informal,
+		GrammarNote:  geminiResp.GrammarNote,
+		Glosses:      geminiResp.Glosses,
+	}
+
+	entry, err := s.translationRepo.RecordLookup(ctx, userID, text, normalizeText(text), baseLang, targetLang, geminiResp.Translation)
+	if err != nil {
+		// The translation itself succeeded, so don't fail the request
+		s.logger.Warn("Failed to record translation memory", zap.Error(err))
+	} else {
+		resp.ID = &entry.ID
+	}
+
+	return resp, nil
+}
+
+func (s *ContentService) ListTranslations(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.TranslationMemory, error) {
+	return s.translationRepo.ListByUser(ctx, userID, limit, offset)
+}
+
+// PromoteTranslation turns a past translation into a study item so it is
+// quizzed and reviewed like daily content. The translated text is the word to
+// learn and the original text is its meaning. Promoting the same translation
+// twice, or a translation of a word the user already studies, returns the
+// existing item. Returns nil if the translation doesn't exist.
+func (s *ContentService) PromoteTranslation(ctx context.Context, profile *models.Profile, translationID uuid.UUID) (*models.DailyContent, error) {
+	userID := profile.UserID
+
+	entry, err := s.translationRepo.GetByID(ctx, userID, translationID)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	if entry.ContentID != nil {
+		content, err := s.contentRepo.GetByID(ctx, userID, *entry.ContentID)
+		if err != nil {
+			return nil, err
+		}
+		if content != nil {
+			return content, nil
+		}
+	}
+
+	if utf8.RuneCountInString(entry.Translation) > maxStudyWordLength {
+		return nil, fmt.Errorf("%w: translation is longer than %d characters", ErrInvalidPromotion, maxStudyWordLength)
+	}
+
+	// Index under the profile's scope so the word dedupes against the rest of
+	// the vocabulary, and return the item the user already has for it
+	scope := vocabularyScope(profile)
+	lemma := normalizeLemma(entry.Translation, scope)
+	contentID, err := s.lemmaRepo.GetContentID(ctx, userID, scope, lemma)
 	if err != nil {
-		s.logger.Warn("Failed to check translate rate limit", zap.Error(err))
-	} else if count >= 50 { // Max 50 translations per day
-		return nil, fmt.Errorf("daily translation rate limit exceeded")
+		return nil, err
+	}
+	if contentID != nil {
+		existing, err := s.contentRepo.GetByID(ctx, userID, *contentID)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			if err := s.translationRepo.SetContentID(ctx, entry.ID, existing.ID); err != nil {
+				return nil, err
+			}
+			return existing, nil
+		}
+	}
+
+	content := &models.DailyContent{
+		UserID:  userID,
+		Date:    time.Now(),
+		Word:    entry.Translation,
+		Meaning: entry.SourceText,
+		Source:  models.ContentSourceTranslation,
+	}
+	if err := s.contentRepo.Insert(ctx, content); err != nil {
+		return nil, fmt.Errorf("failed to create study item: %w", err)
 	}
 
-	// Increment rate limit
-	if _, err := s.cacheRepo.IncrementRateLimit(ctx, userID, "translate"); err != nil {
-		s.logger.Warn("Failed to increment translate rate limit", zap.Error(err))
+	if err := s.translationRepo.SetContentID(ctx, entry.ID, content.ID); err != nil {
+		return nil, err
 	}
 
-	geminiResp, err := s.geminiService.Translate(ctx, req.Text, req.TargetLang, req.BaseLang)
+	if err := s.lemmaRepo.Add(ctx, userID, scope, lemma, &content.ID); err != nil {
+		s.logger.Warn("Failed to index lemma", zap.Error(err), zap.String("lemma", lemma))
+	}
+
+	return content, nil
+}
+
+// AddCustomWord stores a word the user met themselves as a study item,
+// enriched with a meaning and examples by Gemini. It enters the review queue
+// right away so it is quizzed and planned like daily content. Adding a word
+// the user already has returns the existing item and false.
+func (s *ContentService) AddCustomWord(ctx context.Context, profile *models.Profile, req *models.AddWordRequest) (*models.DailyContent, bool, error) {
+	userID := profile.UserID
+	scope := vocabularyScope(profile)
+
+	lemma := normalizeLemma(req.Word, scope)
+	if lemma == "" {
+		return nil, false, fmt.Errorf("%w: word is empty", ErrInputRejected)
+	}
+
+	contentID, err := s.lemmaRepo.GetContentID(ctx, userID, scope, lemma)
 	if err != nil {
-		return nil, fmt.Errorf("failed to translate: %w", err)
+		return nil, false, err
+	}
+	if contentID != nil {
+		existing, err := s.contentRepo.GetByID(ctx, userID, *contentID)
+		if err != nil {
+			return nil, false, err
+		}
+		if existing != nil {
+			return existing, false, nil
+		}
+	}
+
+	resp, err := s.geminiService.EnrichWord(ctx, profile, req.Word, req.Context)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to enrich word: %w", err)
+	}
+
+	content := &models.DailyContent{
+		UserID:         userID,
+		Date:           time.Now(),
+		Word:           resp.Word,
+		Meaning:        resp.Meaning,
+		ExamplesTarget: resp.ExamplesTarget,
+		ExamplesBase:   resp.ExamplesBase,
+		LexicalInfo:    resp.LexicalInfo,
+		Source:         models.ContentSourceCustom,
+	}
+	if err := s.contentRepo.Insert(ctx, content); err != nil {
+		return nil, false, fmt.Errorf("failed to create study item: %w", err)
+	}
+
+	if err := s.lemmaRepo.Add(ctx, userID, scope, lemma, &content.ID); err != nil {
+		s.logger.Warn("Failed to index lemma", zap.Error(err), zap.String("lemma", lemma))
+	}
+
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	if _, err := s.masteryRepo.Upsert(ctx, userID, content.ID, 0, &tomorrow); err != nil {
+		s.logger.Warn("Failed to schedule custom word for review", zap.Error(err))
 	}
 
-	return &models.TranslateResponse{
-		Translation: geminiResp.Translation,
-	}, nil
+	return content, true, nil
 }
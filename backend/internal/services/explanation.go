@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"lexipath-backend/internal/models"
+
+	"go.uber.org/zap"
+)
+
+// explainAnswer sets the explanation of a wrong answer. Explanations are
+// built from templates when the wrong answer can be recognized, such as the
+// meaning of another word the user studies, and written by the LLM
+// otherwise. Either way they are cached per question and wrong answer. A
+// failure is logged rather than failing the answer.
+func (s *QuizService) explainAnswer(ctx context.Context, profile *models.Profile, quizLog *models.QuizLog) {
+	answer := normalizeAnswer(quizLog.UserAnswer)
+
+	cached, err := s.cacheRepo.GetAnswerExplanation(ctx, quizLog.ContentID, quizLog.QuizType, quizLog.Question, answer)
+	if err != nil {
+		s.logger.Warn("Failed to get cached answer explanation", zap.Error(err))
+	}
+	if cached != nil {
+		quizLog.Explanation = cached
+		return
+	}
+
+	explanation, err := s.buildExplanation(ctx, profile, quizLog, answer)
+	if err != nil {
+		s.logger.Warn("Failed to explain wrong answer", zap.Error(err), zap.String("content_id", quizLog.ContentID.String()))
+		return
+	}
+	quizLog.Explanation = explanation
+
+	if err := s.cacheRepo.SetAnswerExplanation(ctx, quizLog.ContentID, quizLog.QuizType, quizLog.Question, answer, explanation); err != nil {
+		s.logger.Warn("Failed to cache answer explanation", zap.Error(err))
+	}
+}
+
+func (s *QuizService) buildExplanation(ctx context.Context, profile *models.Profile, quizLog *models.QuizLog, answer string) (*models.AnswerExplanation, error) {
+	content, err := s.contentRepo.GetByID(ctx, profile.UserID, quizLog.ContentID)
+	if err != nil {
+		return nil, err
+	}
+	if content == nil {
+		return nil, ErrContentNotFound
+	}
+
+	// A wrong answer is often another word the user studies, or its meaning
+	var other *models.DailyContent
+	if answer != "" && quizLog.Rubric == nil && quizLog.QuizType != models.QuizTypeWordOrder {
+		if other, err = s.contentRepo.FindByWordOrMeaning(ctx, profile.UserID, content.ID, answer); err != nil {
+			return nil, err
+		}
+	}
+
+	explanation := templateExplanation(content, quizLog, other, answer)
+	if explanation.WhyWrong != "" && explanation.Example != "" {
+		return explanation, nil
+	}
+
+	geminiResp, err := s.geminiService.ExplainWrongAnswer(ctx, profile, content, quizLog)
+	if err != nil {
+		return nil, err
+	}
+
+	llmExplanation := &models.AnswerExplanation{
+		WhyWrong:     strings.TrimSpace(geminiResp.WhyWrong),
+		CorrectUsage: strings.TrimSpace(geminiResp.CorrectUsage),
+		Example:      explanation.Example,
+		Hint:         strings.TrimSpace(geminiResp.Hint),
+		Source:       models.ExplanationSourceLLM,
+	}
+	// Prefer an example the user has already seen in the content
+	if llmExplanation.Example == "" {
+		llmExplanation.Example = strings.TrimSpace(geminiResp.Example)
+	}
+	if llmExplanation.CorrectUsage == "" {
+		llmExplanation.CorrectUsage = explanation.CorrectUsage
+	}
+	if llmExplanation.Hint == "" {
+		llmExplanation.Hint = explanation.Hint
+	}
+
+	return llmExplanation, nil
+}
+
+// templateExplanation explains a wrong answer without the LLM. WhyWrong is
+// left empty when the answer isn't recognized, and Example when the content
+// has no example sentences.
+func templateExplanation(content *models.DailyContent, quizLog *models.QuizLog, other *models.DailyContent, answer string) *models.AnswerExplanation {
+	explanation := &models.AnswerExplanation{
+		CorrectUsage: fmt.Sprintf("\"%s\" means \"%s\".", content.Word, content.Meaning),
+		Example:      exampleWithWord(content),
+		Hint:         templateHint(content, quizLog),
+		Source:       models.ExplanationSourceTemplate,
+	}
+
+	userAnswer := strings.TrimSpace(quizLog.UserAnswer)
+	switch {
+	case answer == "":
+		explanation.WhyWrong = "No answer was given."
+	case quizLog.Rubric != nil:
+		explanation.WhyWrong = quizLog.Rubric.Explanation
+	case quizLog.QuizType == models.QuizTypeWordOrder:
+		explanation.WhyWrong = fmt.Sprintf("The words are out of order. The sentence reads \"%s\".", quizLog.CorrectAnswer)
+	case other != nil && normalizeAnswer(other.Meaning) == answer:
+		explanation.WhyWrong = fmt.Sprintf("\"%s\" is what \"%s\" means, not \"%s\".", userAnswer, other.Word, content.Word)
+	case other != nil:
+		explanation.WhyWrong = fmt.Sprintf("\"%s\" means \"%s\", which doesn't fit here.", other.Word, other.Meaning)
+	default:
+		if form := inflectionForm(content, answer); form != "" {
+			explanation.WhyWrong = fmt.Sprintf("\"%s\" is the %s form of \"%s\", but the answer needs \"%s\".", userAnswer, form, content.Word, quizLog.CorrectAnswer)
+		}
+	}
+
+	return explanation
+}
+
+// exampleWithWord returns the first target-language example that uses the
+// word, or the first example if none do.
+func exampleWithWord(content *models.DailyContent) string {
+	if len(content.ExamplesTarget) == 0 {
+		return ""
+	}
+	for _, example := range content.ExamplesTarget {
+		if mentionsWord(content, example) {
+			return example
+		}
+	}
+	return content.ExamplesTarget[0]
+}
+
+// mentionsWord reports whether the sentence contains the word or one of its
+// forms, ignoring case and accents.
+func mentionsWord(content *models.DailyContent, sentence string) bool {
+	folded := foldAccents(normalizeAnswer(sentence))
+	forms := []string{content.Word}
+	for _, form := range content.Inflections {
+		forms = append(forms, form)
+	}
+	for _, form := range forms {
+		if form := foldAccents(normalizeAnswer(form)); form != "" && strings.Contains(folded, form) {
+			return true
+		}
+	}
+	return false
+}
+
+// inflectionForm returns the name of the word's form that the answer is,
+// such as "plural", or "" if it isn't one.
+func inflectionForm(content *models.DailyContent, answer string) string {
+	for form, value := range content.Inflections {
+		if normalizeAnswer(value) == answer {
+			return form
+		}
+	}
+	return ""
+}
+
+// templateHint gives a hint that doesn't reveal the answer: the shape of a
+// typed answer, or what is known about the word for a multiple choice one.
+func templateHint(content *models.DailyContent, quizLog *models.QuizLog) string {
+	correct := strings.TrimSpace(quizLog.CorrectAnswer)
+	if quizLog.QuizType == models.QuizTypeFillBlank && correct != "" {
+		first, _ := utf8.DecodeRuneInString(correct)
+		if words := len(strings.Fields(correct)); words > 1 {
+			return fmt.Sprintf("The answer is %d words, starting with \"%c\".", words, first)
+		}
+		return fmt.Sprintf("The answer starts with \"%c\" and has %d letters.", first, utf8.RuneCountInString(correct))
+	}
+
+	switch {
+	case content.PartOfSpeech != "" && content.Gender != "":
+		return fmt.Sprintf("\"%s\" is a %s %s.", content.Word, content.Gender, content.PartOfSpeech)
+	case content.PartOfSpeech != "":
+		return fmt.Sprintf("\"%s\" is a %s.", content.Word, content.PartOfSpeech)
+	case content.Pronunciation != "":
+		return fmt.Sprintf("\"%s\" is pronounced %s.", content.Word, content.Pronunciation)
+	default:
+		return fmt.Sprintf("Read the example aloud to tie \"%s\" to its meaning.", content.Word)
+	}
+}
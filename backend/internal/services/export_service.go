@@ -0,0 +1,304 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+
+	"lexipath-backend/internal/models"
+	"lexipath-backend/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// exportPageSize is how many items are loaded from the database at a time.
+const exportPageSize = 500
+
+// ErrInvalidExportFormat is returned for an export format that isn't supported.
+var ErrInvalidExportFormat = errors.New("invalid export format")
+
+type ExportFormat string
+
+const (
+	ExportFormatCSV  ExportFormat = "csv"
+	ExportFormatAnki ExportFormat = "anki"
+	ExportFormatJSON ExportFormat = "json"
+)
+
+// ContentType is the MIME type of an export in this format.
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportFormatCSV:
+		return "text/csv; charset=utf-8"
+	case ExportFormatJSON:
+		return "application/json; charset=utf-8"
+	default:
+		return "text/tab-separated-values; charset=utf-8"
+	}
+}
+
+// Filename is the download name of an export in this format. Anki only
+// offers .txt files in its import dialog.
+func (f ExportFormat) Filename() string {
+	switch f {
+	case ExportFormatCSV:
+		return "lexipath-vocabulary.csv"
+	case ExportFormatJSON:
+		return "lexipath-vocabulary.json"
+	default:
+		return "lexipath-vocabulary.txt"
+	}
+}
+
+// ParseExportFormat validates a requested export format.
+func ParseExportFormat(value string) (ExportFormat, error) {
+	switch format := ExportFormat(strings.ToLower(value)); format {
+	case ExportFormatCSV, ExportFormatAnki, ExportFormatJSON:
+		return format, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidExportFormat, value)
+}
+
+type ExportService struct {
+	contentRepo *repositories.ContentRepository
+	logger      *zap.Logger
+}
+
+func NewExportService(contentRepo *repositories.ContentRepository, logger *zap.Logger) *ExportService {
+	return &ExportService{
+		contentRepo: contentRepo,
+		logger:      logger,
+	}
+}
+
+// exportWriter writes one export format item by item.
+type exportWriter interface {
+	begin() error
+	write(item *models.ContentProgress) error
+	end() error
+}
+
+// ExportVocabulary streams the user's vocabulary to w, loading it from the
+// database a page at a time so large histories are never held in memory.
+func (s *ExportService) ExportVocabulary(ctx context.Context, userID uuid.UUID, format ExportFormat, w io.Writer) error {
+	var writer exportWriter
+	switch format {
+	case ExportFormatCSV:
+		writer = newCSVExportWriter(w)
+	case ExportFormatAnki:
+		writer = newAnkiExportWriter(w)
+	case ExportFormatJSON:
+		writer = &jsonExportWriter{w: w}
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidExportFormat, format)
+	}
+
+	// The first page is loaded before anything is written, so a failing
+	// query can still be reported to the client as an error.
+	items, err := s.contentRepo.ListWithProgress(ctx, userID, models.ContentFilter{}, nil, false, exportPageSize)
+	if err != nil {
+		return err
+	}
+
+	if err := writer.begin(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	exported := 0
+	for {
+		for _, item := range items {
+			if err := writer.write(item); err != nil {
+				return fmt.Errorf("failed to write export: %w", err)
+			}
+		}
+		exported += len(items)
+		if len(items) < exportPageSize {
+			break
+		}
+		last := items[len(items)-1]
+		after := &models.ContentCursor{Date: last.Date, ID: last.ID}
+		items, err = s.contentRepo.ListWithProgress(ctx, userID, models.ContentFilter{}, after, false, exportPageSize)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := writer.end(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	s.logger.Info("Exported vocabulary",
+		zap.String("user_id", userID.String()),
+		zap.String("format", string(format)),
+		zap.Int("items", exported))
+
+	return nil
+}
+
+var csvExportHeader = []string{
+	"word", "meaning", "examples_target", "examples_base", "part_of_speech",
+	"pronunciation", "romanization", "gender", "tags", "source", "date",
+	"mastery_score", "next_review_date", "quiz_attempts", "quiz_accuracy",
+}
+
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func newCSVExportWriter(w io.Writer) *csvExportWriter {
+	return &csvExportWriter{w: csv.NewWriter(w)}
+}
+
+func (e *csvExportWriter) begin() error {
+	return e.w.Write(csvExportHeader)
+}
+
+func (e *csvExportWriter) write(item *models.ContentProgress) error {
+	record := []string{
+		item.Word,
+		item.Meaning,
+		strings.Join(item.ExamplesTarget, " | "),
+		strings.Join(item.ExamplesBase, " | "),
+		item.PartOfSpeech,
+		item.Pronunciation,
+		item.Romanization,
+		item.Gender,
+		strings.Join(item.Tags, " "),
+		string(item.Source),
+		item.Date.Format("2006-01-02"),
+		"",
+		"",
+		strconv.Itoa(item.QuizAttempts),
+		"",
+	}
+	if item.MasteryScore != nil {
+		record[11] = strconv.Itoa(*item.MasteryScore)
+	}
+	if item.NextReviewDate != nil {
+		record[12] = item.NextReviewDate.Format("2006-01-02")
+	}
+	if item.QuizAccuracy != nil {
+		record[14] = strconv.FormatFloat(*item.QuizAccuracy, 'f', 2, 64)
+	}
+	return e.w.Write(record)
+}
+
+func (e *csvExportWriter) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// ankiExportWriter writes Anki's plain-text import format. The file headers
+// tell Anki the separator, that fields contain HTML and which column holds
+// tags, so the file imports without adjusting any options.
+type ankiExportWriter struct {
+	w *csv.Writer
+}
+
+func newAnkiExportWriter(w io.Writer) *ankiExportWriter {
+	writer := csv.NewWriter(w)
+	writer.Comma = '\t'
+	return &ankiExportWriter{w: writer}
+}
+
+func (e *ankiExportWriter) begin() error {
+	if err := e.w.Write([]string{"#separator:tab"}); err != nil {
+		return err
+	}
+	if err := e.w.Write([]string{"#html:true"}); err != nil {
+		return err
+	}
+	if err := e.w.Write([]string{"#columns:Front", "Back", "Pronunciation", "Examples", "Tags"}); err != nil {
+		return err
+	}
+	return e.w.Write([]string{"#tags column:5"})
+}
+
+func (e *ankiExportWriter) write(item *models.ContentProgress) error {
+	front := html.EscapeString(item.Word)
+	if item.Romanization != "" {
+		front += "<br>" + html.EscapeString(item.Romanization)
+	}
+
+	back := html.EscapeString(item.Meaning)
+	if item.PartOfSpeech != "" {
+		details := item.PartOfSpeech
+		if item.Gender != "" {
+			details += ", " + item.Gender
+		}
+		back += "<br><i>" + html.EscapeString(details) + "</i>"
+	}
+
+	var examples []string
+	for i, example := range item.ExamplesTarget {
+		line := html.EscapeString(example)
+		if i < len(item.ExamplesBase) && item.ExamplesBase[i] != "" {
+			line += " — " + html.EscapeString(item.ExamplesBase[i])
+		}
+		examples = append(examples, line)
+	}
+
+	// Anki tags are space separated, so spaces inside a tag become underscores.
+	tags := []string{"lexipath", "lexipath::" + string(item.Source)}
+	for _, tag := range item.Tags {
+		tags = append(tags, strings.Join(strings.Fields(tag), "_"))
+	}
+
+	return e.w.Write([]string{
+		ankiField(front),
+		ankiField(back),
+		ankiField(html.EscapeString(item.Pronunciation)),
+		ankiField(strings.Join(examples, "<br>")),
+		strings.Join(tags, " "),
+	})
+}
+
+func (e *ankiExportWriter) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// ankiField keeps a field on one line, since Anki reads one note per line.
+func ankiField(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "<br>")
+	value = strings.ReplaceAll(value, "\n", "<br>")
+	return strings.ReplaceAll(value, "\t", " ")
+}
+
+// jsonExportWriter writes a JSON array one element at a time.
+type jsonExportWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonExportWriter) begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonExportWriter) write(item *models.ContentProgress) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	if e.count > 0 {
+		if _, err := io.WriteString(e.w, ","); err != nil {
+			return err
+		}
+	}
+	e.count++
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonExportWriter) end() error {
+	_, err := io.WriteString(e.w, "]")
+	return err
+}
@@ -0,0 +1,340 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"lexipath-backend/internal/config"
+	"lexipath-backend/internal/models"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Accent modes for typed answers that differ from the correct answer only
+// in diacritics.
+const (
+	// AccentModeStrict grades them wrong.
+	AccentModeStrict = "strict"
+	// AccentModeLenient grades them close, pointing out the accents.
+	AccentModeLenient = "lenient"
+	// AccentModeIgnore grades them exact.
+	AccentModeIgnore = "ignore"
+)
+
+// GradeResult is the grade of a quiz answer with feedback for close answers.
+// Free-text answers also carry the rubric they were scored with.
+type GradeResult struct {
+	Grade    models.AnswerGrade
+	Feedback string
+	Rubric   *models.AnswerRubric
+}
+
+// Correct reports whether the answer counts as correct. Close answers
+// don't, since a typo within the tolerance can be a different word, such as
+// "cosa" for "casa"; they earn partial credit instead.
+func (r GradeResult) Correct() bool {
+	return r.Grade == models.AnswerGradeExact
+}
+
+// closeAnswerCredit is the mastery credit for a close answer without a
+// rubric.
+const closeAnswerCredit = 0.5
+
+// Minimum rubric scores, out of 100, for free-text answers to be graded
+// exact or close.
+const (
+	rubricExactScore = 80
+	rubricCloseScore = 50
+)
+
+type rubricCriterion struct {
+	name        string
+	max         int
+	description string
+}
+
+// freeTextRubric is what free-text answers are scored on. The maximums add
+// up to 100.
+var freeTextRubric = []rubricCriterion{
+	{"word_use", 40, "uses the word, or a correct form of it, with the right meaning"},
+	{"task_fit", 30, "answers the question or fits the situation it describes"},
+	{"grammar", 20, "the answer is grammatically correct"},
+	{"naturalness", 10, "a native speaker would phrase it this way"},
+}
+
+// scoreRubric totals the LLM's criterion scores into a rubric and grade.
+// Scores are clamped to each criterion's range, and criteria the LLM left
+// out score zero, so the total doesn't depend on the LLM's arithmetic.
+func scoreRubric(resp *models.GeminiRubricResponse) GradeResult {
+	scored := make(map[string]models.RubricCriterion, len(resp.Criteria))
+	for _, criterion := range resp.Criteria {
+		scored[strings.ToLower(strings.TrimSpace(criterion.Name))] = criterion
+	}
+
+	rubric := &models.AnswerRubric{Explanation: strings.TrimSpace(resp.Explanation)}
+	for _, criterion := range freeTextRubric {
+		result := models.RubricCriterion{Name: criterion.name, Max: criterion.max}
+		if got, ok := scored[criterion.name]; ok {
+			result.Score = max(0, min(got.Score, criterion.max))
+			result.Comment = strings.TrimSpace(got.Comment)
+		}
+		rubric.Criteria = append(rubric.Criteria, result)
+		rubric.Score += result.Score
+	}
+	rubric.Credit = float64(rubric.Score) / 100
+
+	grade := models.AnswerGradeWrong
+	if rubric.Score >= rubricExactScore {
+		grade = models.AnswerGradeExact
+	} else if rubric.Score >= rubricCloseScore {
+		grade = models.AnswerGradeClose
+	}
+
+	return GradeResult{Grade: grade, Feedback: rubric.Explanation, Rubric: rubric}
+}
+
+// answerCredit is how much an answer counts toward mastery, from 0 to 1.
+// Answers with a rubric count by its score; others count fully when exact
+// and partly when close.
+func answerCredit(quizLog *models.QuizLog) float64 {
+	if quizLog.Rubric != nil {
+		return quizLog.Rubric.Credit
+	}
+	switch quizLog.Grade {
+	case models.AnswerGradeExact:
+		return 1
+	case models.AnswerGradeClose:
+		return closeAnswerCredit
+	default:
+		return 0
+	}
+}
+
+// gradeAnswer grades a quiz answer against the correct one. Multiple choice
+// answers must match an option, up to case and spacing; typed answers are
+// also accepted with a missing or wrong article, missing accents according
+// to the accent mode, or a typo within a distance scaled to the word length.
+func gradeAnswer(cfg config.GradingConfig, quizType models.QuizType, correctAnswer, userAnswer, language string) GradeResult {
+	correct := normalizeAnswer(correctAnswer)
+	answer := normalizeAnswer(userAnswer)
+
+	if answer == correct {
+		return GradeResult{Grade: models.AnswerGradeExact}
+	}
+	if quizType == models.QuizTypeMCQ || answer == "" {
+		return GradeResult{Grade: models.AnswerGradeWrong}
+	}
+
+	correctArticle, correctBare := splitArticle(correct, language)
+	answerArticle, answerBare := splitArticle(answer, language)
+
+	if answerBare == correctBare && correctArticle != answerArticle {
+		if correctArticle == "" {
+			// An article the expected answer doesn't include is harmless
+			return GradeResult{Grade: models.AnswerGradeExact}
+		}
+		return GradeResult{
+			Grade:    models.AnswerGradeClose,
+			Feedback: fmt.Sprintf("Almost! Check the article: %s", strings.TrimSpace(correctAnswer)),
+		}
+	}
+
+	if foldAccents(answerBare) == foldAccents(correctBare) {
+		switch cfg.AccentMode {
+		case AccentModeIgnore:
+			return GradeResult{Grade: models.AnswerGradeExact}
+		case AccentModeStrict:
+			return GradeResult{
+				Grade:    models.AnswerGradeWrong,
+				Feedback: fmt.Sprintf("Watch the accents: %s", strings.TrimSpace(correctAnswer)),
+			}
+		default:
+			return GradeResult{
+				Grade:    models.AnswerGradeClose,
+				Feedback: fmt.Sprintf("Almost! Watch the accents: %s", strings.TrimSpace(correctAnswer)),
+			}
+		}
+	}
+
+	folded := foldAccents(correctBare)
+	if editDistance(foldAccents(answerBare), folded) <= typoTolerance(folded) {
+		return GradeResult{
+			Grade:    models.AnswerGradeClose,
+			Feedback: fmt.Sprintf("Almost! Check the spelling: %s", strings.TrimSpace(correctAnswer)),
+		}
+	}
+
+	return GradeResult{Grade: models.AnswerGradeWrong}
+}
+
+// gradeMatching scores a matching answer per pair. All pairs right is
+// exact, at least half is close, and the rubric's credit is the share of
+// pairs matched.
+func gradeMatching(terms []string, correctAnswer, userAnswer string) GradeResult {
+	var expected, given []string
+	if err := json.Unmarshal([]byte(correctAnswer), &expected); err != nil || len(expected) == 0 {
+		return GradeResult{Grade: models.AnswerGradeWrong}
+	}
+	if err := json.Unmarshal([]byte(userAnswer), &given); err != nil {
+		return GradeResult{
+			Grade:    models.AnswerGradeWrong,
+			Feedback: "Give one meaning per word, as a list in the order of the words.",
+		}
+	}
+
+	rubric := &models.AnswerRubric{}
+	var matched int
+	var mistakes []string
+	for i, meaning := range expected {
+		term := fmt.Sprintf("word %d", i+1)
+		if i < len(terms) {
+			term = terms[i]
+		}
+		criterion := models.RubricCriterion{Name: term, Max: 1}
+		if i < len(given) && normalizeAnswer(given[i]) == normalizeAnswer(meaning) {
+			criterion.Score = 1
+			matched++
+		} else {
+			criterion.Comment = fmt.Sprintf("\"%s\" means \"%s\".", term, meaning)
+			mistakes = append(mistakes, criterion.Comment)
+		}
+		rubric.Criteria = append(rubric.Criteria, criterion)
+	}
+	rubric.Score = int(math.Round(100 * float64(matched) / float64(len(expected))))
+	rubric.Credit = float64(matched) / float64(len(expected))
+
+	if matched == len(expected) {
+		rubric.Explanation = "All pairs matched."
+		return GradeResult{Grade: models.AnswerGradeExact, Rubric: rubric}
+	}
+
+	rubric.Explanation = fmt.Sprintf("%d of %d pairs matched. %s", matched, len(expected), strings.Join(mistakes, " "))
+	grade := models.AnswerGradeWrong
+	if 2*matched >= len(expected) {
+		grade = models.AnswerGradeClose
+	}
+	return GradeResult{Grade: grade, Feedback: rubric.Explanation, Rubric: rubric}
+}
+
+// gradeWordOrder compares a rebuilt sentence word by word. A longer
+// sentence with just two neighbouring words swapped is close.
+func gradeWordOrder(correctAnswer, userAnswer string) GradeResult {
+	expected := strings.Fields(normalizeAnswer(correctAnswer))
+	given := strings.Fields(normalizeAnswer(userAnswer))
+
+	// Map each word to a rune so the edit distance counts whole words
+	ids := map[string]rune{}
+	encode := func(words []string) string {
+		var b strings.Builder
+		for _, word := range words {
+			id, ok := ids[word]
+			if !ok {
+				id = rune(0xE000 + len(ids))
+				ids[word] = id
+			}
+			b.WriteRune(id)
+		}
+		return b.String()
+	}
+
+	distance := editDistance(encode(given), encode(expected))
+	switch {
+	case distance == 0:
+		return GradeResult{Grade: models.AnswerGradeExact}
+	case distance == 1 && len(expected) >= 6 && len(given) == len(expected):
+		return GradeResult{
+			Grade:    models.AnswerGradeClose,
+			Feedback: fmt.Sprintf("Almost! Check the word order: %s", strings.TrimSpace(correctAnswer)),
+		}
+	default:
+		return GradeResult{Grade: models.AnswerGradeWrong}
+	}
+}
+
+// normalizeAnswer puts an answer in NFC, lowercases it, collapses spaces,
+// unifies apostrophes and drops trailing punctuation.
+func normalizeAnswer(answer string) string {
+	answer = norm.NFC.String(answer)
+	answer = strings.ToLower(answer)
+	answer = strings.NewReplacer("’", "'", "‘", "'", "`", "'").Replace(answer)
+	answer = strings.Join(strings.Fields(answer), " ")
+	return strings.TrimRightFunc(answer, func(r rune) bool {
+		return r == '.' || r == '!' || r == '?' || r == ',' || r == ';' || r == '¡' || r == '¿'
+	})
+}
+
+// splitArticle separates a leading article from a normalized answer.
+func splitArticle(answer, language string) (string, string) {
+	first, rest, _ := strings.Cut(answer, " ")
+	for _, article := range leadingArticles[language] {
+		if strings.HasSuffix(article, "'") {
+			if strings.HasPrefix(answer, article) && len(answer) > len(article) {
+				return article, answer[len(article):]
+			}
+			continue
+		}
+		if first == article && rest != "" {
+			return article, rest
+		}
+	}
+	return "", answer
+}
+
+// foldAccents removes diacritics, so "café" and "cafe" compare equal.
+func foldAccents(value string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(value) {
+		if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(r)
+		}
+	}
+	return norm.NFC.String(b.String())
+}
+
+// typoTolerance is how many edits an answer may be off by and still be
+// close. Short words must be exact, since one edit often makes another word.
+func typoTolerance(word string) int {
+	switch length := utf8.RuneCountInString(word); {
+	case length <= 3:
+		return 0
+	case length <= 6:
+		return 1
+	case length <= 10:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// editDistance is the Damerau-Levenshtein distance between two strings, in
+// runes, counting a swap of adjacent letters as one edit.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return prev[len(rb)]
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"testing"
+
+	"lexipath-backend/internal/config"
+	"lexipath-backend/internal/models"
+)
+
+func TestNormalizeAnswer(t *testing.T) {
+	tests := []struct {
+		name   string
+		answer string
+		want   string
+	}{
+		{"lowercases and trims", "  El Gato ", "el gato"},
+		{"collapses spaces", "el \t gato\n negro", "el gato negro"},
+		{"drops trailing punctuation", "¿Dónde está?!", "¿dónde está"},
+		{"keeps inner punctuation", "sí, claro.", "sí, claro"},
+		{"unifies apostrophes", "l’homme", "l'homme"},
+		{"composes accents", "café", "café"},
+		{"empty", "  ", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeAnswer(tt.answer); got != tt.want {
+				t.Errorf("normalizeAnswer(%q) = %q, want %q", tt.answer, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitArticle(t *testing.T) {
+	tests := []struct {
+		name        string
+		answer      string
+		language    string
+		wantArticle string
+		wantBare    string
+	}{
+		{"spanish article", "el gato", "es", "el", "gato"},
+		{"german accusative article", "einen hund", "de", "einen", "hund"},
+		{"french elided article", "l'homme", "fr", "l'", "homme"},
+		{"italian elided indefinite", "un'amica", "it", "un'", "amica"},
+		{"article alone is the word", "la", "es", "", "la"},
+		{"elided article alone", "l'", "fr", "", "l'"},
+		{"no article", "gato", "es", "", "gato"},
+		{"article of another language", "the cat", "es", "", "the cat"},
+		{"unknown language", "el gato", "xx", "", "el gato"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			article, bare := splitArticle(tt.answer, tt.language)
+			if article != tt.wantArticle || bare != tt.wantBare {
+				t.Errorf("splitArticle(%q, %q) = (%q, %q), want (%q, %q)",
+					tt.answer, tt.language, article, bare, tt.wantArticle, tt.wantBare)
+			}
+		})
+	}
+}
+
+func TestGradeAnswer(t *testing.T) {
+	lenient := config.GradingConfig{AccentMode: AccentModeLenient}
+	strict := config.GradingConfig{AccentMode: AccentModeStrict}
+	ignore := config.GradingConfig{AccentMode: AccentModeIgnore}
+
+	tests := []struct {
+		name     string
+		cfg      config.GradingConfig
+		quizType models.QuizType
+		correct  string
+		answer   string
+		language string
+		want     models.AnswerGrade
+	}{
+		{"exact up to case and punctuation", lenient, models.QuizTypeFillBlank, "el gato", "El Gato.", "es", models.AnswerGradeExact},
+		{"empty answer", lenient, models.QuizTypeFillBlank, "gato", "  ", "es", models.AnswerGradeWrong},
+		{"multiple choice must match", lenient, models.QuizTypeMCQ, "house", "hous", "en", models.AnswerGradeWrong},
+		{"multiple choice match", lenient, models.QuizTypeMCQ, "house", " House ", "en", models.AnswerGradeExact},
+		{"missing article", lenient, models.QuizTypeFillBlank, "el gato", "gato", "es", models.AnswerGradeClose},
+		{"wrong article", lenient, models.QuizTypeFillBlank, "el gato", "la gato", "es", models.AnswerGradeClose},
+		{"extra article", lenient, models.QuizTypeFillBlank, "gato", "el gato", "es", models.AnswerGradeExact},
+		{"missing elided article", lenient, models.QuizTypeFillBlank, "l'homme", "homme", "fr", models.AnswerGradeClose},
+		{"missing accent lenient", lenient, models.QuizTypeFillBlank, "café", "cafe", "fr", models.AnswerGradeClose},
+		{"missing accent strict", strict, models.QuizTypeFillBlank, "café", "cafe", "fr", models.AnswerGradeWrong},
+		{"missing accent ignored", ignore, models.QuizTypeFillBlank, "café", "cafe", "fr", models.AnswerGradeExact},
+		{"article and accent", lenient, models.QuizTypeFillBlank, "el niño", "el nino", "es", models.AnswerGradeClose},
+		{"typo in a medium word", lenient, models.QuizTypeFillBlank, "ventana", "ventena", "es", models.AnswerGradeClose},
+		{"swapped letters", lenient, models.QuizTypeFillBlank, "amigo", "aimgo", "es", models.AnswerGradeClose},
+		{"short word must be exact", lenient, models.QuizTypeFillBlank, "sol", "sal", "es", models.AnswerGradeWrong},
+		{"too many typos", lenient, models.QuizTypeFillBlank, "gato", "gtoa", "es", models.AnswerGradeWrong},
+		{"different word", lenient, models.QuizTypeFillBlank, "gato", "perro", "es", models.AnswerGradeWrong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := gradeAnswer(tt.cfg, tt.quizType, tt.correct, tt.answer, tt.language)
+			if got.Grade != tt.want {
+				t.Errorf("gradeAnswer(%q, %q) = %s, want %s", tt.correct, tt.answer, got.Grade, tt.want)
+			}
+			if got.Grade == models.AnswerGradeClose && got.Feedback == "" {
+				t.Errorf("gradeAnswer(%q, %q) is close without feedback", tt.correct, tt.answer)
+			}
+		})
+	}
+}
+
+func TestGradeResultCorrect(t *testing.T) {
+	tests := []struct {
+		grade models.AnswerGrade
+		want  bool
+	}{
+		{models.AnswerGradeExact, true},
+		{models.AnswerGradeClose, false},
+		{models.AnswerGradeWrong, false},
+	}
+
+	for _, tt := range tests {
+		if got := (GradeResult{Grade: tt.grade}).Correct(); got != tt.want {
+			t.Errorf("GradeResult{Grade: %s}.Correct() = %v, want %v", tt.grade, got, tt.want)
+		}
+	}
+}
+
+func TestAnswerCredit(t *testing.T) {
+	tests := []struct {
+		name    string
+		quizLog *models.QuizLog
+		want    float64
+	}{
+		{"exact", &models.QuizLog{Grade: models.AnswerGradeExact}, 1},
+		{"close", &models.QuizLog{Grade: models.AnswerGradeClose}, closeAnswerCredit},
+		{"wrong", &models.QuizLog{Grade: models.AnswerGradeWrong}, 0},
+		{"rubric wins", &models.QuizLog{Grade: models.AnswerGradeClose, Rubric: &models.AnswerRubric{Credit: 0.65}}, 0.65},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := answerCredit(tt.quizLog); got != tt.want {
+				t.Errorf("answerCredit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypoTolerance(t *testing.T) {
+	tests := []struct {
+		word string
+		want int
+	}{
+		{"sol", 0},
+		{"casa", 1},
+		{"niño", 1},
+		{"ventan", 1},
+		{"ventana", 2},
+		{"habitación", 2},
+		{"conocimiento", 3},
+	}
+
+	for _, tt := range tests {
+		if got := typoTolerance(tt.word); got != tt.want {
+			t.Errorf("typoTolerance(%q) = %d, want %d", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"gato", "", 4},
+		{"", "gato", 4},
+		{"gato", "gato", 0},
+		{"gato", "pato", 1},
+		{"gato", "gatos", 1},
+		{"amigo", "aimgo", 1},
+		{"kitten", "sitting", 3},
+		{"niño", "nino", 1},
+		{"über", "uber", 1},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestGradeMatching(t *testing.T) {
+	terms := []string{"gato", "perro", "casa", "sol"}
+	correct := `["cat", "dog", "house", "sun"]`
+
+	tests := []struct {
+		name       string
+		correct    string
+		answer     string
+		want       models.AnswerGrade
+		wantCredit float64
+	}{
+		{"all pairs", correct, `["Cat", "dog ", "house.", "sun"]`, models.AnswerGradeExact, 1},
+		{"half the pairs", correct, `["cat", "dog", "sun", "house"]`, models.AnswerGradeClose, 0.5},
+		{"one pair", correct, `["cat", "sun", "dog", "house"]`, models.AnswerGradeWrong, 0.25},
+		{"too few answers", correct, `["cat"]`, models.AnswerGradeWrong, 0.25},
+		{"answer not a list", correct, `cat, dog`, models.AnswerGradeWrong, 0},
+		{"stored answer not a list", `cat`, `["cat"]`, models.AnswerGradeWrong, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := gradeMatching(terms, tt.correct, tt.answer)
+			if got.Grade != tt.want {
+				t.Errorf("gradeMatching(%s) = %s, want %s", tt.answer, got.Grade, tt.want)
+			}
+			var credit float64
+			if got.Rubric != nil {
+				credit = got.Rubric.Credit
+			}
+			if credit != tt.wantCredit {
+				t.Errorf("gradeMatching(%s) credit = %v, want %v", tt.answer, credit, tt.wantCredit)
+			}
+		})
+	}
+}
+
+func TestGradeWordOrder(t *testing.T) {
+	long := "mi hermana vive en una casa grande"
+
+	tests := []struct {
+		name    string
+		correct string
+		answer  string
+		want    models.AnswerGrade
+	}{
+		{"same order", long, "Mi hermana vive en una casa grande.", models.AnswerGradeExact},
+		{"neighbours swapped", long, "mi hermana vive en una grande casa", models.AnswerGradeClose},
+		{"swap in a short sentence", "el gato come", "el come gato", models.AnswerGradeWrong},
+		{"word missing", long, "mi hermana vive en una casa", models.AnswerGradeWrong},
+		{"scrambled", long, "casa grande una en vive hermana mi", models.AnswerGradeWrong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gradeWordOrder(tt.correct, tt.answer); got.Grade != tt.want {
+				t.Errorf("gradeWordOrder(%q) = %s, want %s", tt.answer, got.Grade, tt.want)
+			}
+		})
+	}
+}
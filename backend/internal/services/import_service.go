@@ -0,0 +1,492 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"lexipath-backend/internal/models"
+	"lexipath-backend/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Limits for vocabulary imports.
+const (
+	MaxImportFileSize    = 5 << 20
+	maxImportRows        = 5000
+	maxImportErrors      = 200
+	maxImportFieldLength = 1000
+	maxImportTags        = 10
+	maxConcurrentImports = 2
+	// importProgressInterval is how many rows are processed between progress saves.
+	importProgressInterval = 100
+)
+
+// importGuardFeature labels imports in prompt guard flags.
+const importGuardFeature = "import"
+
+// ErrInvalidImport is returned when an import file or its options can't be used.
+var ErrInvalidImport = errors.New("invalid import")
+
+type ImportFormat string
+
+const (
+	ImportFormatCSV ImportFormat = "csv"
+	ImportFormatTSV ImportFormat = "tsv"
+)
+
+// importFields are the fields a column can be mapped to, in the order used
+// when a file has no header and no mapping is given.
+var importFields = []string{"word", "meaning", "examples", "tags", "mastery"}
+
+// ImportOptions describe how to read an import file.
+type ImportOptions struct {
+	Format ImportFormat
+	// HasHeader means the first line names the columns.
+	HasHeader bool
+	// Mapping maps a field (word, meaning, examples, tags, mastery) to a
+	// column, given as a header name or a 1-based column number. Fields not
+	// mapped are looked up by header name, or by position without a header.
+	Mapping map[string]string
+	// SeedMastery is the mastery score, 0 to 100, for rows without a mastery
+	// column. Nil starts them at 0.
+	SeedMastery *int
+}
+
+// ParseImportMapping reads a mapping such as "word=Front,meaning=Back,tags=3".
+func ParseImportMapping(spec string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	for _, pair := range strings.Split(spec, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		field, column, ok := strings.Cut(pair, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		column = strings.TrimSpace(column)
+		if !ok || column == "" || !isImportField(field) {
+			return nil, fmt.Errorf("%w: invalid mapping %q", ErrInvalidImport, pair)
+		}
+		mapping[field] = column
+	}
+	return mapping, nil
+}
+
+func isImportField(field string) bool {
+	for _, f := range importFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// importRow is a parsed line of an import file. Err is set when the line
+// can't be imported.
+type importRow struct {
+	line     int
+	word     string
+	meaning  string
+	examples []string
+	tags     []string
+	mastery  int
+	err      string
+}
+
+// ImportService imports vocabulary from CSV/TSV files into the user's study
+// items. Imports run in the background, a few at a time.
+type ImportService struct {
+	importRepo  *repositories.ImportRepository
+	contentRepo *repositories.ContentRepository
+	lemmaRepo   *repositories.LemmaRepository
+	masteryRepo *repositories.MasteryRepository
+	guard       *PromptGuard
+	slots       chan struct{}
+	logger      *zap.Logger
+}
+
+func NewImportService(importRepo *repositories.ImportRepository, contentRepo *repositories.ContentRepository, lemmaRepo *repositories.LemmaRepository, masteryRepo *repositories.MasteryRepository, guard *PromptGuard, logger *zap.Logger) *ImportService {
+	return &ImportService{
+		importRepo:  importRepo,
+		contentRepo: contentRepo,
+		lemmaRepo:   lemmaRepo,
+		masteryRepo: masteryRepo,
+		guard:       guard,
+		slots:       make(chan struct{}, maxConcurrentImports),
+		logger:      logger,
+	}
+}
+
+// StartImport parses the file and creates a job whose rows are imported in
+// the background. Problems with the file as a whole are returned right away.
+func (s *ImportService) StartImport(ctx context.Context, profile *models.Profile, filename string, data []byte, opts ImportOptions) (*models.ImportJob, error) {
+	rows, err := s.parseImport(profile.UserID, data, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.importRepo.Create(ctx, profile.UserID, filename, len(rows))
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		s.slots <- struct{}{}
+		defer func() { <-s.slots }()
+
+		// Detached from the request, which ends as soon as the job is created
+		s.process(context.Background(), profile, job, rows)
+	}()
+
+	return job, nil
+}
+
+// RunImport parses and imports the file before returning, for the CLI.
+func (s *ImportService) RunImport(ctx context.Context, profile *models.Profile, filename string, data []byte, opts ImportOptions) (*models.ImportJob, error) {
+	rows, err := s.parseImport(profile.UserID, data, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := s.importRepo.Create(ctx, profile.UserID, filename, len(rows))
+	if err != nil {
+		return nil, err
+	}
+
+	s.process(ctx, profile, job, rows)
+	return job, nil
+}
+
+func (s *ImportService) GetJob(ctx context.Context, userID, jobID uuid.UUID) (*models.ImportJob, error) {
+	return s.importRepo.GetByID(ctx, userID, jobID)
+}
+
+func (s *ImportService) process(ctx context.Context, profile *models.Profile, job *models.ImportJob, rows []importRow) {
+	job.Status = models.ImportJobStatusRunning
+	if err := s.importRepo.Update(ctx, job); err != nil {
+		s.logger.Warn("Failed to update import job", zap.Error(err), zap.String("job_id", job.ID.String()))
+	}
+
+	scope := vocabularyScope(profile)
+	for i, row := range rows {
+		if ctx.Err() != nil {
+			break
+		}
+
+		imported, err := s.storeRow(ctx, profile.UserID, scope, row)
+		switch {
+		case err != nil:
+			job.Failed++
+			if len(job.Errors) < maxImportErrors {
+				job.Errors = append(job.Errors, models.ImportRowError{Line: row.line, Error: err.Error()})
+			}
+		case imported:
+			job.Imported++
+		default:
+			job.Skipped++
+		}
+
+		if (i+1)%importProgressInterval == 0 {
+			if err := s.importRepo.Update(ctx, job); err != nil {
+				s.logger.Warn("Failed to update import job", zap.Error(err), zap.String("job_id", job.ID.String()))
+			}
+		}
+	}
+
+	finishedAt := time.Now().UTC()
+	job.FinishedAt = &finishedAt
+	job.Status = models.ImportJobStatusCompleted
+	if ctx.Err() != nil {
+		job.Status = models.ImportJobStatusFailed
+	}
+	if err := s.importRepo.Update(context.Background(), job); err != nil {
+		s.logger.Error("Failed to finish import job", zap.Error(err), zap.String("job_id", job.ID.String()))
+	}
+
+	s.logger.Info("Vocabulary import finished",
+		zap.String("job_id", job.ID.String()),
+		zap.String("user_id", job.UserID.String()),
+		zap.Int("imported", job.Imported),
+		zap.Int("skipped", job.Skipped),
+		zap.Int("failed", job.Failed))
+}
+
+// storeRow stores one row. It reports false without an error when the user
+// already has the word.
+func (s *ImportService) storeRow(ctx context.Context, userID uuid.UUID, scope string, row importRow) (bool, error) {
+	if row.err != "" {
+		return false, errors.New(row.err)
+	}
+
+	lemma := normalizeLemma(row.word, scope)
+	seen, err := s.lemmaRepo.Exists(ctx, userID, scope, lemma)
+	if err != nil {
+		return false, errors.New("failed to check for duplicates")
+	}
+	if seen {
+		return false, nil
+	}
+
+	content := &models.DailyContent{
+		UserID:         userID,
+		Date:           time.Now(),
+		Word:           row.word,
+		Meaning:        row.meaning,
+		ExamplesTarget: row.examples,
+		Tags:           row.tags,
+		Source:         models.ContentSourceImport,
+	}
+	if err := s.contentRepo.Insert(ctx, content); err != nil {
+		s.logger.Warn("Failed to import row", zap.Error(err), zap.Int("line", row.line))
+		return false, errors.New("failed to save word")
+	}
+
+	if err := s.lemmaRepo.Add(ctx, userID, scope, lemma, &content.ID); err != nil {
+		s.logger.Warn("Failed to index lemma", zap.Error(err), zap.String("lemma", lemma))
+	}
+
+	nextReview := importReviewDate(row.mastery, time.Now())
+	if _, err := s.masteryRepo.Upsert(ctx, userID, content.ID, row.mastery, &nextReview); err != nil {
+		s.logger.Warn("Failed to seed mastery for imported word", zap.Error(err))
+	}
+
+	return true, nil
+}
+
+// importReviewDate schedules the first review of an imported word using the
+// same intervals as quiz results.
+func importReviewDate(score int, now time.Time) time.Time {
+	switch {
+	case score < 50:
+		return now.AddDate(0, 0, 1)
+	case score < 80:
+		return now.AddDate(0, 0, 3)
+	default:
+		return now.AddDate(0, 0, 7)
+	}
+}
+
+// parseImport reads the rows of an import file. Problems with single rows are
+// set on the row rather than returned.
+func (s *ImportService) parseImport(userID uuid.UUID, data []byte, opts ImportOptions) ([]importRow, error) {
+	if len(data) > MaxImportFileSize {
+		return nil, fmt.Errorf("%w: file is larger than %d bytes", ErrInvalidImport, MaxImportFileSize)
+	}
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("%w: file is not UTF-8", ErrInvalidImport)
+	}
+	if opts.SeedMastery != nil && (*opts.SeedMastery < 0 || *opts.SeedMastery > 100) {
+		return nil, fmt.Errorf("%w: seed mastery must be between 0 and 100", ErrInvalidImport)
+	}
+
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	switch opts.Format {
+	case ImportFormatCSV:
+	case ImportFormatTSV:
+		reader.Comma = '\t'
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q", ErrInvalidImport, opts.Format)
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var header []string
+	if opts.HasHeader {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: file is empty", ErrInvalidImport)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+		}
+		header = record
+	}
+
+	columns, err := resolveImportColumns(header, opts.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	seedMastery := 0
+	if opts.SeedMastery != nil {
+		seedMastery = *opts.SeedMastery
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		if len(rows) == maxImportRows {
+			return nil, fmt.Errorf("%w: file has more than %d rows", ErrInvalidImport, maxImportRows)
+		}
+
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, s.buildImportRow(userID, line, record, columns, seedMastery))
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: file has no rows", ErrInvalidImport)
+	}
+
+	return rows, nil
+}
+
+// resolveImportColumns returns the 0-based column of each mapped field.
+func resolveImportColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	columns := make(map[string]int)
+
+	for field, column := range mapping {
+		if n, err := strconv.Atoi(column); err == nil {
+			if n < 1 {
+				return nil, fmt.Errorf("%w: column numbers start at 1", ErrInvalidImport)
+			}
+			columns[field] = n - 1
+			continue
+		}
+		index := headerIndex(header, column)
+		if index < 0 {
+			return nil, fmt.Errorf("%w: no column named %q", ErrInvalidImport, column)
+		}
+		columns[field] = index
+	}
+
+	for i, field := range importFields {
+		if _, ok := columns[field]; ok {
+			continue
+		}
+		if header != nil {
+			if index := headerIndex(header, field); index >= 0 {
+				columns[field] = index
+			}
+		} else if len(mapping) == 0 {
+			columns[field] = i
+		}
+	}
+
+	if _, ok := columns["word"]; !ok {
+		return nil, fmt.Errorf("%w: no column mapped to word", ErrInvalidImport)
+	}
+	if _, ok := columns["meaning"]; !ok {
+		return nil, fmt.Errorf("%w: no column mapped to meaning", ErrInvalidImport)
+	}
+
+	return columns, nil
+}
+
+func headerIndex(header []string, name string) int {
+	for i, column := range header {
+		if strings.EqualFold(strings.TrimSpace(column), name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isBlankRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// buildImportRow reads one line of an import file. Its text is run through
+// the prompt guard, since it ends up in quiz, grading and feedback prompts.
+func (s *ImportService) buildImportRow(userID uuid.UUID, line int, record []string, columns map[string]int, seedMastery int) importRow {
+	field := func(name string) string {
+		index, ok := columns[name]
+		if !ok || index >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[index])
+	}
+
+	row := importRow{
+		line:    line,
+		word:    field("word"),
+		meaning: field("meaning"),
+		mastery: seedMastery,
+	}
+
+	switch {
+	case row.word == "":
+		row.err = "word is empty"
+		return row
+	case row.meaning == "":
+		row.err = "meaning is empty"
+		return row
+	case utf8.RuneCountInString(row.word) > maxLabelLength:
+		row.err = fmt.Sprintf("word is longer than %d characters", maxLabelLength)
+		return row
+	case utf8.RuneCountInString(row.meaning) > maxImportFieldLength:
+		row.err = fmt.Sprintf("meaning is longer than %d characters", maxImportFieldLength)
+		return row
+	}
+
+	sanitize := func(name, value string, maxLength int) (string, bool) {
+		cleaned, err := s.guard.SanitizeText(userID, importGuardFeature, value, maxLength)
+		if err != nil {
+			row.err = fmt.Sprintf("%s: %v", name, err)
+			return "", false
+		}
+		return cleaned, true
+	}
+
+	var ok bool
+	if row.word, ok = sanitize("word", row.word, maxLabelLength); !ok {
+		return row
+	}
+	if row.meaning, ok = sanitize("meaning", row.meaning, maxImportFieldLength); !ok {
+		return row
+	}
+
+	// Multiple examples in one cell are separated by "|"
+	for _, example := range strings.Split(field("examples"), "|") {
+		if example = strings.TrimSpace(example); example == "" {
+			continue
+		}
+		if example, ok = sanitize("example", example, maxImportFieldLength); !ok {
+			return row
+		}
+		row.examples = append(row.examples, example)
+	}
+
+	seen := make(map[string]bool)
+	for _, tag := range strings.FieldsFunc(field("tags"), func(r rune) bool { return r == ',' || r == ';' || r == ' ' }) {
+		if tag, ok = sanitize("tag", strings.ToLower(tag), maxLabelLength); !ok {
+			return row
+		}
+		if !seen[tag] && len(row.tags) < maxImportTags {
+			seen[tag] = true
+			row.tags = append(row.tags, tag)
+		}
+	}
+
+	if value := field("mastery"); value != "" {
+		score, err := strconv.Atoi(value)
+		if err != nil || score < 0 || score > 100 {
+			row.err = "mastery must be a number between 0 and 100"
+			return row
+		}
+		row.mastery = score
+	}
+
+	return row
+}
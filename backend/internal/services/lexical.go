@@ -0,0 +1,170 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"lexipath-backend/internal/models"
+)
+
+// Limits for lexical fields, in characters unless noted.
+const (
+	maxPronunciationLength = 100
+	maxRomanizationLength  = 100
+	maxInflections         = 8
+	maxInflectionLength    = 60
+)
+
+var partsOfSpeech = map[string]bool{
+	"noun":         true,
+	"verb":         true,
+	"adjective":    true,
+	"adverb":       true,
+	"pronoun":      true,
+	"preposition":  true,
+	"conjunction":  true,
+	"interjection": true,
+	"determiner":   true,
+	"numeral":      true,
+	"particle":     true,
+	"phrase":       true,
+}
+
+// grammaticalGenders lists the genders nouns can take, keyed by lowercase
+// language code. Languages not listed have no grammatical gender.
+var grammaticalGenders = map[string][]string{
+	"es": {"masculine", "feminine"},
+	"fr": {"masculine", "feminine"},
+	"it": {"masculine", "feminine"},
+	"pt": {"masculine", "feminine"},
+	"ca": {"masculine", "feminine"},
+	"ar": {"masculine", "feminine"},
+	"he": {"masculine", "feminine"},
+	"hi": {"masculine", "feminine"},
+	"de": {"masculine", "feminine", "neuter"},
+	"ru": {"masculine", "feminine", "neuter"},
+	"uk": {"masculine", "feminine", "neuter"},
+	"pl": {"masculine", "feminine", "neuter"},
+	"cs": {"masculine", "feminine", "neuter"},
+	"el": {"masculine", "feminine", "neuter"},
+	"nl": {"common", "neuter"},
+	"sv": {"common", "neuter"},
+	"da": {"common", "neuter"},
+	"no": {"common", "neuter"},
+}
+
+var genderAbbreviations = map[string]string{
+	"m": "masculine",
+	"f": "feminine",
+	"n": "neuter",
+	"c": "common",
+}
+
+// nonLatinScripts are the languages, by lowercase code, whose words get a
+// romanization.
+var nonLatinScripts = map[string]bool{
+	"ja": true, "zh": true, "ko": true, "ru": true, "uk": true, "el": true,
+	"ar": true, "he": true, "hi": true, "th": true, "fa": true,
+}
+
+// lexicalLanguage is the language code a profile's words are written in.
+// Industry tracks are in English.
+func lexicalLanguage(profile *models.Profile) string {
+	if profile.GoalType == models.GoalTypeLanguage && profile.TargetLang != nil {
+		return normalizeLang(*profile.TargetLang)
+	}
+	return "en"
+}
+
+// sanitizeLexicalInfo normalizes the optional lexical fields and clears any
+// that are malformed or don't apply to the language, such as a gender in a
+// language without grammatical gender. It returns the names of the fields it
+// cleared so callers can log them.
+func sanitizeLexicalInfo(info *models.LexicalInfo, language string) []string {
+	var dropped []string
+
+	info.PartOfSpeech = strings.ToLower(strings.TrimSpace(info.PartOfSpeech))
+	if info.PartOfSpeech != "" && !partsOfSpeech[info.PartOfSpeech] {
+		info.PartOfSpeech = ""
+		dropped = append(dropped, "part_of_speech")
+	}
+
+	if info.Pronunciation != "" {
+		info.Pronunciation = normalizePronunciation(info.Pronunciation)
+		if info.Pronunciation == "" {
+			dropped = append(dropped, "pronunciation")
+		}
+	}
+
+	info.Romanization = strings.TrimSpace(info.Romanization)
+	if info.Romanization != "" && (!nonLatinScripts[language] || !isLatinText(info.Romanization) ||
+		utf8.RuneCountInString(info.Romanization) > maxRomanizationLength) {
+		info.Romanization = ""
+		dropped = append(dropped, "romanization")
+	}
+
+	info.Gender = strings.ToLower(strings.TrimSpace(info.Gender))
+	if full, ok := genderAbbreviations[info.Gender]; ok {
+		info.Gender = full
+	}
+	if info.Gender != "" && !validGender(info.Gender, info.PartOfSpeech, language) {
+		info.Gender = ""
+		dropped = append(dropped, "gender")
+	}
+
+	if len(info.Inflections) > 0 {
+		kept := make(map[string]string)
+		for form, value := range info.Inflections {
+			form, value = strings.TrimSpace(form), strings.TrimSpace(value)
+			if form == "" || value == "" || len(kept) == maxInflections ||
+				utf8.RuneCountInString(form) > maxInflectionLength || utf8.RuneCountInString(value) > maxInflectionLength {
+				continue
+			}
+			kept[form] = value
+		}
+		if len(kept) < len(info.Inflections) {
+			dropped = append(dropped, "inflections")
+		}
+		info.Inflections = kept
+	}
+
+	return dropped
+}
+
+// normalizePronunciation returns IPA wrapped in slashes, or "" if the value
+// doesn't look like a transcription.
+func normalizePronunciation(value string) string {
+	value = strings.TrimSpace(value)
+	value = strings.TrimSpace(strings.Trim(value, "/[]"))
+	if value == "" || utf8.RuneCountInString(value) > maxPronunciationLength {
+		return ""
+	}
+	for _, r := range value {
+		if unicode.IsDigit(r) || strings.ContainsRune("<>{}\"`/", r) {
+			return ""
+		}
+	}
+	return "/" + value + "/"
+}
+
+func validGender(gender, partOfSpeech, language string) bool {
+	if partOfSpeech != "" && partOfSpeech != "noun" {
+		return false
+	}
+	for _, allowed := range grammaticalGenders[language] {
+		if gender == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func isLatinText(value string) bool {
+	for _, r := range value {
+		if unicode.IsLetter(r) && !unicode.Is(unicode.Latin, r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"lexipath-backend/internal/models"
+	"lexipath-backend/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidLexiconEntry is returned when a curated entry fails validation.
+var ErrInvalidLexiconEntry = errors.New("invalid lexicon entry")
+
+// LexiconService manages the global word bank that daily content is drawn
+// from before falling back to per-user generation.
+type LexiconService struct {
+	lexiconRepo *repositories.LexiconRepository
+	logger      *zap.Logger
+}
+
+func NewLexiconService(lexiconRepo *repositories.LexiconRepository, logger *zap.Logger) *LexiconService {
+	return &LexiconService{
+		lexiconRepo: lexiconRepo,
+		logger:      logger,
+	}
+}
+
+// catalogKey returns the word bank key a profile draws from.
+func catalogKey(profile *models.Profile) *models.LexiconEntry {
+	key := &models.LexiconEntry{Level: profile.Level}
+	if profile.GoalType == models.GoalTypeLanguage {
+		if profile.TargetLang != nil {
+			key.TargetLang = normalizeLang(*profile.TargetLang)
+		}
+		if profile.BaseLang != nil {
+			key.BaseLang = normalizeLang(*profile.BaseLang)
+		}
+	} else if profile.IndustrySector != nil {
+		key.Sector = strings.ToLower(strings.TrimSpace(*profile.IndustrySector))
+	}
+	return key
+}
+
+// DrawUnseen returns a word bank entry for the profile that the user hasn't
+// been taught yet, or nil when the catalog is exhausted for them.
+func (s *LexiconService) DrawUnseen(ctx context.Context, profile *models.Profile) (*models.LexiconEntry, error) {
+	return s.lexiconRepo.GetUnseenEntry(ctx, profile.UserID, vocabularyScope(profile), catalogKey(profile))
+}
+
+// AddGenerated stores a freshly generated word in the word bank so other
+// users with the same key can be served it without another Gemini call.
+func (s *LexiconService) AddGenerated(ctx context.Context, profile *models.Profile, lemma string, resp *models.GeminiDailyContentResponse) (*models.LexiconEntry, error) {
+	entry := catalogKey(profile)
+	entry.Lemma = lemma
+	entry.Word = resp.Word
+	entry.Meaning = resp.Meaning
+	entry.ExamplesTarget = resp.ExamplesTarget
+	entry.ExamplesBase = resp.ExamplesBase
+	entry.LexicalInfo = resp.LexicalInfo
+	entry.Origin = models.LexiconOriginGenerated
+
+	if err := s.lexiconRepo.Upsert(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// AddCurated validates and stores hand-curated entries, replacing any
+// generated entry with the same word.
+func (s *LexiconService) AddCurated(ctx context.Context, inputs []models.LexiconEntryInput) ([]*models.LexiconEntry, error) {
+	entries := make([]*models.LexiconEntry, 0, len(inputs))
+	for i, input := range inputs {
+		if err := validateLexiconEntryInput(&input); err != nil {
+			return nil, fmt.Errorf("%w %d: %v", ErrInvalidLexiconEntry, i, err)
+		}
+
+		entry := &models.LexiconEntry{
+			TargetLang:     normalizeLang(input.TargetLang),
+			BaseLang:       normalizeLang(input.BaseLang),
+			Level:          input.Level,
+			Sector:         strings.ToLower(strings.TrimSpace(input.Sector)),
+			Word:           strings.TrimSpace(input.Word),
+			Meaning:        strings.TrimSpace(input.Meaning),
+			ExamplesTarget: input.ExamplesTarget,
+			ExamplesBase:   input.ExamplesBase,
+			LexicalInfo:    input.LexicalInfo,
+			Origin:         models.LexiconOriginCurated,
+		}
+		entry.Lemma = normalizeLemma(entry.Word, entry.TargetLang)
+
+		language := entry.TargetLang
+		if language == "" {
+			language = "en"
+		}
+		if dropped := sanitizeLexicalInfo(&entry.LexicalInfo, language); len(dropped) > 0 {
+			return nil, fmt.Errorf("%w %d: invalid %s", ErrInvalidLexiconEntry, i, strings.Join(dropped, ", "))
+		}
+		entries = append(entries, entry)
+	}
+
+	for _, entry := range entries {
+		if err := s.lexiconRepo.Upsert(ctx, entry); err != nil {
+			return nil, err
+		}
+	}
+
+	s.logger.Info("Added curated lexicon entries", zap.Int("count", len(entries)))
+
+	return entries, nil
+}
+
+// inappropriateReportThreshold is how many user reports disable a generated entry.
+const inappropriateReportThreshold = 3
+
+// ReportInappropriate records a user's report that an entry was inappropriate.
+func (s *LexiconService) ReportInappropriate(ctx context.Context, id uuid.UUID) error {
+	return s.lexiconRepo.ReportInappropriate(ctx, id, inappropriateReportThreshold)
+}
+
+// SetActive enables or disables an entry. Returns false if it doesn't exist.
+func (s *LexiconService) SetActive(ctx context.Context, id uuid.UUID, active bool) (bool, error) {
+	return s.lexiconRepo.SetActive(ctx, id, active)
+}
+
+func validateLexiconEntryInput(input *models.LexiconEntryInput) error {
+	switch input.Level {
+	case models.LanguageLevelBeginner, models.LanguageLevelIntermediate, models.LanguageLevelAdvanced:
+	default:
+		return fmt.Errorf("invalid level %q", input.Level)
+	}
+	isLanguage := input.TargetLang != "" || input.BaseLang != ""
+	if isLanguage == (input.Sector != "") {
+		return fmt.Errorf("either target_lang and base_lang or sector is required")
+	}
+	if isLanguage && (input.TargetLang == "" || input.BaseLang == "") {
+		return fmt.Errorf("both target_lang and base_lang are required for language entries")
+	}
+	if isLanguage && len(input.ExamplesBase) == 0 {
+		return fmt.Errorf("examples_base is required for language entries")
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"strings"
+
+	"lexipath-backend/internal/models"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// normalizeText canonicalizes user text for lookups: Unicode NFC, lower case
+// and single spaces between words.
+func normalizeText(text string) string {
+	return strings.ToLower(collapseText(text))
+}
+
+// collapseText canonicalizes text without folding case, for keys where case
+// changes the meaning, such as German "Sie" and "sie": Unicode NFC and single
+// spaces between words.
+func collapseText(text string) string {
+	return strings.Join(strings.Fields(norm.NFC.String(text)), " ")
+}
+
+// normalizeLang canonicalizes a language code or name such as "ES" or " es ".
+func normalizeLang(lang string) string {
+	return strings.ToLower(strings.TrimSpace(lang))
+}
+
+// leadingArticles are the articles that may lead a word, keyed by lowercase
+// language code. They are stripped when building a lemma and tolerated when
+// grading. Elided forms such as l' end in an apostrophe and attach to the
+// word; English "to" is included so infinitives match their bare form.
+var leadingArticles = map[string][]string{
+	"en": {"the", "a", "an", "to"},
+	"es": {"el", "la", "los", "las", "un", "una", "unos", "unas"},
+	"fr": {"le", "la", "les", "l'", "un", "une", "des"},
+	"de": {"der", "die", "das", "den", "dem", "des", "ein", "eine", "einen", "einem", "einer", "eines"},
+	"it": {"il", "lo", "la", "i", "gli", "le", "l'", "un'", "un", "uno", "una"},
+	"pt": {"o", "a", "os", "as", "um", "uma", "uns", "umas"},
+	"ca": {"el", "la", "els", "les", "l'", "un", "una"},
+	"nl": {"de", "het", "een"},
+}
+
+// normalizeLemma builds the key used to decide whether a user has already
+// been taught a word: normalized text without a leading article.
+func normalizeLemma(word, language string) string {
+	lemma := normalizeText(word)
+	for _, article := range leadingArticles[language] {
+		if strings.HasSuffix(article, "'") {
+			if rest := strings.TrimPrefix(lemma, article); rest != lemma && rest != "" {
+				return strings.TrimSpace(rest)
+			}
+			continue
+		}
+		if rest := strings.TrimPrefix(lemma, article+" "); rest != lemma && rest != "" {
+			return rest
+		}
+	}
+	return lemma
+}
+
+// vocabularyScope is the language key a profile's vocabulary is tracked under:
+// the target language for language learners, the sector for industry tracks.
+func vocabularyScope(profile *models.Profile) string {
+	if profile.GoalType == models.GoalTypeLanguage && profile.TargetLang != nil {
+		return normalizeLang(*profile.TargetLang)
+	}
+	if profile.IndustrySector != nil {
+		return "industry:" + strings.ToLower(strings.TrimSpace(*profile.IndustrySector))
+	}
+	return "industry"
+}
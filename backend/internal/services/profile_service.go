@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"lexipath-backend/internal/models"
 	"lexipath-backend/internal/repositories"
@@ -59,5 +60,15 @@ func (s *ProfileService) validateProfileRequest(req *models.UpsertProfileRequest
 		}
 	}
 
+	if req.Timezone != nil && *req.Timezone != "" {
+		if _, err := time.LoadLocation(*req.Timezone); err != nil {
+			return fmt.Errorf("timezone must be an IANA timezone name")
+		}
+	}
+
+	if req.DailyWordCount != nil && (*req.DailyWordCount < models.MinDailyWordCount || *req.DailyWordCount > models.MaxDailyWordCount) {
+		return fmt.Errorf("daily_word_count must be between %d and %d", models.MinDailyWordCount, models.MaxDailyWordCount)
+	}
+
 	return nil
 }
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrInputRejected is returned when user-supplied text can't safely be sent
+// to the LLM, or when the LLM output for it doesn't look like what was asked for.
+var ErrInputRejected = errors.New("input rejected")
+
+// Length caps for user text interpolated into prompts, in characters.
+const (
+	maxTranslateTextLength  = 500
+	maxLabelLength          = 100
+	maxCustomWordLength     = 100
+	maxWordContextLength    = 500
+	maxFreeTextAnswerLength = 500
+	maxContentTextLength    = 1000
+)
+
+var promptGuardFlagsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "lexipath_prompt_guard_flags_total",
+	Help: "User inputs and LLM outputs flagged by the prompt guard, by feature and reason.",
+}, []string{"feature", "reason"})
+
+// instructionPatterns match common attempts to override the prompt. They
+// also match ordinary sentences such as "he will act as a mentor", so text
+// matching them is flagged but kept; the delimiters keep it from being read
+// as instructions.
+var instructionPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(ignore|disregard|forget|override)\b[^.\n]{0,40}\b(instructions?|prompts?|rules|above|previous|prior)\b`),
+	regexp.MustCompile(`(?i)\byou are (now|no longer)\b`),
+	regexp.MustCompile(`(?i)\b(act|behave|respond) as\b`),
+	regexp.MustCompile(`(?i)\b(system|developer) (prompt|message|instructions?)\b`),
+}
+
+// markupPatterns match role prefixes and the prompt delimiters, which could
+// pass text off as another turn or end the delimited block early. Matches
+// are stripped from the input before it is delimited, delimiters first so a
+// role prefix behind one is still at the start of a line.
+var markupPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)</?\s*user_text\s*>`),
+	regexp.MustCompile(`(?im)^\s*(system|assistant|user)\s*:`),
+}
+
+// labelPattern is what a language name/code or industry sector may contain.
+var labelPattern = regexp.MustCompile(`^[\p{L}\p{M}0-9 &/(),.'-]+$`)
+
+// PromptGuard sanitizes user text before it is interpolated into a prompt
+// and sanity-checks what the LLM returns for it.
+type PromptGuard struct {
+	logger *zap.Logger
+}
+
+func NewPromptGuard(logger *zap.Logger) *PromptGuard {
+	return &PromptGuard{logger: logger}
+}
+
+// SanitizeText normalizes free text, enforces the length cap and strips
+// role prefixes and delimiters. Instruction-like phrases are flagged but
+// left in place. The result should still be placed inside delimitUserText
+// in the prompt.
+func (g *PromptGuard) SanitizeText(userID uuid.UUID, feature, text string, maxLength int) (string, error) {
+	cleaned := strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			return -1
+		}
+		return r
+	}, norm.NFC.String(text)))
+
+	if cleaned == "" {
+		return "", fmt.Errorf("%w: text is empty", ErrInputRejected)
+	}
+	if utf8.RuneCountInString(cleaned) > maxLength {
+		g.flag(userID, feature, "too_long", text)
+		return "", fmt.Errorf("%w: text is longer than %d characters", ErrInputRejected, maxLength)
+	}
+
+	stripped := cleaned
+	for _, pattern := range markupPatterns {
+		stripped = pattern.ReplaceAllString(stripped, " ")
+	}
+	stripped = strings.TrimSpace(stripped)
+
+	if stripped != cleaned {
+		g.flag(userID, feature, "prompt_markup", text)
+	} else if matchesAny(instructionPatterns, cleaned) {
+		g.flag(userID, feature, "instruction_pattern", text)
+	}
+	if stripped == "" {
+		return "", fmt.Errorf("%w: text contains only prompt markup", ErrInputRejected)
+	}
+
+	return stripped, nil
+}
+
+// SanitizeLabel validates a short label such as a language or industry
+// sector that appears in a prompt without delimiters.
+func (g *PromptGuard) SanitizeLabel(userID uuid.UUID, feature, label string) (string, error) {
+	label = strings.TrimSpace(norm.NFC.String(label))
+
+	if !isPlainLabel(label) {
+		g.flag(userID, feature, "invalid_label", label)
+		return "", fmt.Errorf("%w: invalid value %q", ErrInputRejected, label)
+	}
+
+	return label, nil
+}
+
+// FilterLabels silently drops values that wouldn't pass SanitizeLabel. It is
+// meant for lists built from stored data rather than a direct user request.
+func (g *PromptGuard) FilterLabels(values []string) []string {
+	var kept []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); isPlainLabel(value) {
+			kept = append(kept, value)
+		}
+	}
+	return kept
+}
+
+func isPlainLabel(value string) bool {
+	if value == "" || utf8.RuneCountInString(value) > maxLabelLength || !labelPattern.MatchString(value) {
+		return false
+	}
+	// Labels appear in prompts without delimiters, so anything that reads
+	// like an instruction is refused
+	return !matchesAny(instructionPatterns, value)
+}
+
+func matchesAny(patterns []*regexp.Regexp, text string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckTranslation rejects LLM output that doesn't look like a translation of
+// the input: far longer than the source, containing code or echoing the
+// prompt delimiters.
+func (g *PromptGuard) CheckTranslation(userID uuid.UUID, feature, input, output string) error {
+	reason := ""
+	switch {
+	case utf8.RuneCountInString(output) > 3*utf8.RuneCountInString(input)+50:
+		reason = "output_too_long"
+	case strings.Contains(output, "```"):
+		reason = "output_code_block"
+	case strings.Contains(strings.ToLower(output), "user_text"):
+		reason = "output_delimiter_echo"
+	}
+
+	if reason != "" {
+		g.flag(userID, feature, reason, input)
+		return fmt.Errorf("%w: output does not look like a translation", ErrInputRejected)
+	}
+
+	return nil
+}
+
+func (g *PromptGuard) flag(userID uuid.UUID, feature, reason, text string) {
+	promptGuardFlagsTotal.WithLabelValues(feature, reason).Inc()
+
+	if utf8.RuneCountInString(text) > 200 {
+		text = string([]rune(text)[:200]) + "…"
+	}
+	g.logger.Warn("Prompt guard flagged request",
+		zap.String("user_id", userID.String()),
+		zap.String("feature", feature),
+		zap.String("reason", reason),
+		zap.String("text", text))
+}
+
+// delimitUserText wraps sanitized user text in the tags that prompts refer to
+// when telling the model to treat it as data.
+func delimitUserText(text string) string {
+	return "<user_text>\n" + text + "\n</user_text>"
+}
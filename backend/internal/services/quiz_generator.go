@@ -0,0 +1,319 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"lexipath-backend/internal/models"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Quiz generators: whether questions that can be built from the user's
+// content are, or are left to the LLM.
+const (
+	QuizGeneratorLocal = "local"
+	QuizGeneratorLLM   = "llm"
+)
+
+// mcqOptionCount is the number of options in a multiple choice question.
+const mcqOptionCount = 4
+
+// fillBlank replaces the word in a fill-blank sentence.
+const fillBlank = "_____"
+
+// Bounds for the number of pairs in a matching question.
+const (
+	minMatchingPairs = 4
+	maxMatchingPairs = 6
+)
+
+// Bounds for the number of words in a word order sentence. Shorter ones are
+// trivial and longer ones tedious to rebuild.
+const (
+	minWordOrderWords = 3
+	maxWordOrderWords = 12
+)
+
+// plannedQuizType returns the quiz type to plan for the content, falling
+// back to multiple choice when the content lacks what the type is built from.
+func plannedQuizType(content *models.DailyContent, quizType models.QuizType) models.QuizType {
+	switch quizType {
+	case models.QuizTypeFillBlank:
+		if len(content.ExamplesTarget) == 0 {
+			return models.QuizTypeMCQ
+		}
+	case models.QuizTypeWordOrder:
+		if wordOrderSentence(content) == "" {
+			return models.QuizTypeMCQ
+		}
+	case models.QuizTypeReverseTranslation:
+		if len(content.ExamplesTarget) == 0 || len(content.ExamplesBase) == 0 {
+			return models.QuizTypeMCQ
+		}
+	}
+	return quizType
+}
+
+// buildMCQQuiz asks for the word's meaning, with the meanings of the user's
+// other words as distractors, picked by closeness. Returns nil if there
+// aren't enough distinct meanings.
+func buildMCQQuiz(content *models.DailyContent, others []*models.DailyContent, closeness distractorCloseness) *models.GeneratedQuiz {
+	meaning := strings.TrimSpace(content.Meaning)
+	seen := map[string]bool{normalizeAnswer(meaning): true}
+	var distractors []string
+	for _, other := range orderDistractors(content, others, closeness) {
+		distractor := strings.TrimSpace(other.Meaning)
+		key := normalizeAnswer(distractor)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		distractors = append(distractors, distractor)
+	}
+	if len(distractors) < mcqOptionCount-1 {
+		return nil
+	}
+
+	options := append([]string{meaning}, distractors[:mcqOptionCount-1]...)
+	rand.Shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
+
+	return &models.GeneratedQuiz{
+		QuizType:      models.QuizTypeMCQ,
+		Question:      fmt.Sprintf("What does \"%s\" mean?", strings.TrimSpace(content.Word)),
+		Options:       options,
+		CorrectAnswer: meaning,
+	}
+}
+
+// buildFillBlankQuiz blanks out the word in the first example sentence that
+// uses it. The answer is the form the sentence uses, which the question
+// names alongside the meaning when it is an inflection. Returns nil if no
+// example uses the word.
+func buildFillBlankQuiz(content *models.DailyContent) *models.GeneratedQuiz {
+	for _, example := range content.ExamplesTarget {
+		masked, answer, form, ok := maskWord(content, example)
+		if !ok {
+			continue
+		}
+
+		hint := strings.TrimSpace(content.Meaning)
+		if form != "" {
+			hint = fmt.Sprintf("%s, %s", hint, form)
+		}
+		return &models.GeneratedQuiz{
+			QuizType:      models.QuizTypeFillBlank,
+			Question:      fmt.Sprintf("Fill in the blank: %s (%s)", strings.TrimSpace(masked), hint),
+			CorrectAnswer: answer,
+		}
+	}
+	return nil
+}
+
+// buildSentenceQuiz asks for a sentence using the word, with an example
+// that uses it as the reference answer for grading.
+func buildSentenceQuiz(content *models.DailyContent) *models.GeneratedQuiz {
+	reference := exampleWithWord(content)
+	if reference == "" {
+		reference = content.Word
+	}
+
+	return &models.GeneratedQuiz{
+		QuizType:      models.QuizTypeSentence,
+		Question:      fmt.Sprintf("Write a sentence using \"%s\" (%s).", strings.TrimSpace(content.Word), strings.TrimSpace(content.Meaning)),
+		CorrectAnswer: strings.TrimSpace(reference),
+	}
+}
+
+type wordForm struct {
+	// name is the inflection, such as "plural", or "" for the word itself.
+	name  string
+	value string
+}
+
+// wordForms lists the word and its inflections, longest first so that a
+// form containing another is found whole.
+func wordForms(content *models.DailyContent) []wordForm {
+	forms := []wordForm{{value: content.Word}}
+	for name, value := range content.Inflections {
+		forms = append(forms, wordForm{name: name, value: value})
+	}
+	sort.SliceStable(forms, func(i, j int) bool {
+		li, lj := utf8.RuneCountInString(forms[i].value), utf8.RuneCountInString(forms[j].value)
+		if li != lj {
+			return li > lj
+		}
+		return forms[i].name < forms[j].name
+	})
+	return forms
+}
+
+// maskWord replaces the first whole-word occurrence of the word or one of
+// its forms in the sentence with a blank, ignoring case and accents. It
+// returns the masked sentence, the form as stored, which is the answer even
+// if the sentence spells it differently, and the name of the form, "" for
+// the word itself.
+func maskWord(content *models.DailyContent, sentence string) (string, string, string, bool) {
+	runes := []rune(norm.NFC.String(sentence))
+	folded := foldRunes(runes)
+
+	for _, form := range wordForms(content) {
+		target := foldRunes([]rune(norm.NFC.String(strings.TrimSpace(form.value))))
+		if len(target) == 0 {
+			continue
+		}
+		for start := 0; start+len(target) <= len(folded); start++ {
+			end := start + len(target)
+			if string(folded[start:end]) != string(target) {
+				continue
+			}
+			if (start > 0 && isWordRune(runes[start-1])) || (end < len(runes) && isWordRune(runes[end])) {
+				continue
+			}
+			return string(runes[:start]) + fillBlank + string(runes[end:]), strings.TrimSpace(form.value), form.name, true
+		}
+	}
+	return "", "", "", false
+}
+
+// foldRunes lowercases runes and strips their accents one for one, so
+// positions in the result match the input.
+func foldRunes(runes []rune) []rune {
+	folded := make([]rune, len(runes))
+	for i, r := range runes {
+		base, _ := utf8.DecodeRuneInString(norm.NFD.String(string(r)))
+		folded[i] = unicode.ToLower(base)
+	}
+	return folded
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r)
+}
+
+// buildMatchingQuiz pairs the word with the user's recent words, picked by
+// closeness. Words are skipped if their word or meaning repeats one already
+// paired, so every pair is unambiguous. Returns nil if fewer than
+// minMatchingPairs remain.
+func buildMatchingQuiz(content *models.DailyContent, recent []*models.DailyContent, closeness distractorCloseness) *models.GeneratedQuiz {
+	pairs := []*models.DailyContent{content}
+	seenWords := map[string]bool{normalizeAnswer(content.Word): true}
+	seenMeanings := map[string]bool{normalizeAnswer(content.Meaning): true}
+	for _, other := range orderDistractors(content, recent, closeness) {
+		if len(pairs) == maxMatchingPairs {
+			break
+		}
+		word, meaning := normalizeAnswer(other.Word), normalizeAnswer(other.Meaning)
+		if word == "" || meaning == "" || seenWords[word] || seenMeanings[meaning] {
+			continue
+		}
+		seenWords[word] = true
+		seenMeanings[meaning] = true
+		pairs = append(pairs, other)
+	}
+	if len(pairs) < minMatchingPairs {
+		return nil
+	}
+
+	rand.Shuffle(len(pairs), func(i, j int) { pairs[i], pairs[j] = pairs[j], pairs[i] })
+	terms := make([]string, len(pairs))
+	meanings := make([]string, len(pairs))
+	for i, pair := range pairs {
+		terms[i] = strings.TrimSpace(pair.Word)
+		meanings[i] = strings.TrimSpace(pair.Meaning)
+	}
+	correctAnswer, err := json.Marshal(meanings)
+	if err != nil {
+		return nil
+	}
+
+	options := append([]string{}, meanings...)
+	rand.Shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
+
+	return &models.GeneratedQuiz{
+		QuizType:      models.QuizTypeMatching,
+		Question:      "Match each word with its meaning.",
+		Options:       options,
+		Terms:         terms,
+		CorrectAnswer: string(correctAnswer),
+	}
+}
+
+// buildWordOrderQuiz scrambles the words of an example sentence. Returns
+// nil if no example has a suitable length.
+func buildWordOrderQuiz(content *models.DailyContent) *models.GeneratedQuiz {
+	sentence := wordOrderSentence(content)
+	if sentence == "" {
+		return nil
+	}
+
+	words := strings.Fields(sentence)
+	scrambled := append([]string{}, words...)
+	// A shuffle can land on the original order, which would give it away
+	for attempt := 0; attempt < 5; attempt++ {
+		rand.Shuffle(len(scrambled), func(i, j int) { scrambled[i], scrambled[j] = scrambled[j], scrambled[i] })
+		if strings.Join(scrambled, " ") != sentence {
+			break
+		}
+	}
+
+	return &models.GeneratedQuiz{
+		QuizType:      models.QuizTypeWordOrder,
+		Question:      "Put the words in order to make a sentence.",
+		Options:       scrambled,
+		CorrectAnswer: sentence,
+	}
+}
+
+// wordOrderSentence picks the example sentence for a word order question,
+// preferring one that uses the word. Sentence punctuation at either end is
+// dropped, since it would give away the first or last word.
+func wordOrderSentence(content *models.DailyContent) string {
+	var fallback string
+	for _, example := range content.ExamplesTarget {
+		sentence := strings.TrimFunc(strings.TrimSpace(example), func(r rune) bool {
+			return strings.ContainsRune(".!?…¡¿", r)
+		})
+		words := strings.Fields(sentence)
+		if len(words) < minWordOrderWords || len(words) > maxWordOrderWords {
+			continue
+		}
+		sentence = strings.Join(words, " ")
+		if mentionsWord(content, sentence) {
+			return sentence
+		}
+		if fallback == "" {
+			fallback = sentence
+		}
+	}
+	return fallback
+}
+
+// buildReverseTranslationQuiz asks for a base-language example in the target
+// language, preferring a pair whose target sentence uses the word. Examples
+// are paired by position. Returns nil if there is no pair.
+func buildReverseTranslationQuiz(content *models.DailyContent) *models.GeneratedQuiz {
+	count := min(len(content.ExamplesTarget), len(content.ExamplesBase))
+	if count == 0 {
+		return nil
+	}
+
+	index := 0
+	for i := 0; i < count; i++ {
+		if mentionsWord(content, content.ExamplesTarget[i]) {
+			index = i
+			break
+		}
+	}
+
+	return &models.GeneratedQuiz{
+		QuizType:      models.QuizTypeReverseTranslation,
+		Question:      fmt.Sprintf("Translate into the language you're learning: \"%s\"", strings.TrimSpace(content.ExamplesBase[index])),
+		CorrectAnswer: strings.TrimSpace(content.ExamplesTarget[index]),
+	}
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+
+	"lexipath-backend/internal/models"
+)
+
+func newTestContent(word, meaning string, examples ...string) *models.DailyContent {
+	return &models.DailyContent{Word: word, Meaning: meaning, ExamplesTarget: examples}
+}
+
+func TestMaskWord(t *testing.T) {
+	gato := newTestContent("gato", "cat")
+	gato.Inflections = map[string]string{"plural": "gatos"}
+	casa := newTestContent("casa", "house")
+	casa.Inflections = map[string]string{"plural": "casas"}
+
+	tests := []struct {
+		name       string
+		content    *models.DailyContent
+		sentence   string
+		wantMasked string
+		wantAnswer string
+		wantForm   string
+		wantOK     bool
+	}{
+		{"word", newTestContent("sol", "sun"), "Hace sol hoy.", "Hace _____ hoy.", "sol", "", true},
+		{"ignores case", casa, "Casa grande.", "_____ grande.", "casa", "", true},
+		{"missing accent in sentence", newTestContent("café", "coffee"), "Tomo un cafe cada día.", "Tomo un _____ cada día.", "café", "", true},
+		{"decomposed accent in sentence", newTestContent("café", "coffee"), "Un cafe\u0301, por favor.", "Un _____, por favor.", "café", "", true},
+		{"inflection", gato, "Los gatos duermen.", "Los _____ duermen.", "gatos", "plural", true},
+		{"longer form first", casa, "Las casas son casa.", "Las _____ son casa.", "casas", "plural", true},
+		{"whole words only", newTestContent("sol", "sun"), "El solar es grande.", "", "", "", false},
+		{"not in sentence", gato, "El perro ladra.", "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			masked, answer, form, ok := maskWord(tt.content, tt.sentence)
+			if ok != tt.wantOK || masked != tt.wantMasked || answer != tt.wantAnswer || form != tt.wantForm {
+				t.Errorf("maskWord(%q) = (%q, %q, %q, %v), want (%q, %q, %q, %v)",
+					tt.sentence, masked, answer, form, ok, tt.wantMasked, tt.wantAnswer, tt.wantForm, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestBuildMatchingQuiz(t *testing.T) {
+	content := newTestContent("gato", "cat")
+	others := func(pairs ...string) []*models.DailyContent {
+		var contents []*models.DailyContent
+		for i := 0; i+1 < len(pairs); i += 2 {
+			contents = append(contents, newTestContent(pairs[i], pairs[i+1]))
+		}
+		return contents
+	}
+	many := others("perro", "dog", "casa", "house", "sol", "sun", "luna", "moon", "agua", "water", "pan", "bread", "leche", "milk")
+
+	tests := []struct {
+		name      string
+		recent    []*models.DailyContent
+		wantPairs int
+	}{
+		{"minimum pairs", others("perro", "dog", "casa", "house", "sol", "sun"), minMatchingPairs},
+		{"capped at the maximum", many, maxMatchingPairs},
+		{"too few words", others("perro", "dog", "casa", "house"), 0},
+		{"repeated meaning skipped", others("perro", "dog", "can", "Dog.", "casa", "house", "sol", "sun"), minMatchingPairs},
+		{"repeated word skipped", others("perro", "dog", "Perro", "hound", "casa", "house", "sol", "sun"), minMatchingPairs},
+		{"the word's own meaning skipped", others("minino", "cat", "perro", "dog", "casa", "house"), 0},
+		{"empty meaning skipped", others("perro", "dog", "casa", "", "sol", "sun"), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quiz := buildMatchingQuiz(content, tt.recent, distractorsMixed)
+			if tt.wantPairs == 0 {
+				if quiz != nil {
+					t.Fatalf("buildMatchingQuiz() = %v pairs, want nil", quiz.Terms)
+				}
+				return
+			}
+			if quiz == nil {
+				t.Fatalf("buildMatchingQuiz() = nil, want %d pairs", tt.wantPairs)
+			}
+
+			var meanings []string
+			if err := json.Unmarshal([]byte(quiz.CorrectAnswer), &meanings); err != nil {
+				t.Fatalf("correct answer isn't a list: %v", err)
+			}
+			if len(quiz.Terms) != tt.wantPairs || len(meanings) != tt.wantPairs || len(quiz.Options) != tt.wantPairs {
+				t.Fatalf("got %d terms, %d meanings and %d options, want %d of each",
+					len(quiz.Terms), len(meanings), len(quiz.Options), tt.wantPairs)
+			}
+
+			// Every term must be paired with its own meaning, and appear once
+			want := map[string]string{"gato": "cat"}
+			for _, other := range tt.recent {
+				if _, ok := want[other.Word]; !ok {
+					want[other.Word] = other.Meaning
+				}
+			}
+			seen := map[string]bool{}
+			for i, term := range quiz.Terms {
+				if seen[normalizeAnswer(term)] {
+					t.Errorf("term %q appears twice", term)
+				}
+				seen[normalizeAnswer(term)] = true
+				if meanings[i] != want[term] {
+					t.Errorf("term %q is paired with %q, want %q", term, meanings[i], want[term])
+				}
+			}
+			if !seen["gato"] {
+				t.Errorf("terms %v don't include the word", quiz.Terms)
+			}
+		})
+	}
+}
+
+func TestWordOrderSentence(t *testing.T) {
+	tests := []struct {
+		name     string
+		examples []string
+		want     string
+	}{
+		{"prefers a sentence with the word", []string{"Hace mucho frío hoy.", "El gato duerme en casa."}, "El gato duerme en casa"},
+		{"falls back to the first suitable", []string{"Sí.", "Hace mucho frío hoy.", "Llueve mucho en otoño."}, "Hace mucho frío hoy"},
+		{"drops punctuation at both ends", []string{"¿Dónde está el gato?"}, "Dónde está el gato"},
+		{"collapses spaces", []string{"  el   gato  come "}, "el gato come"},
+		{"too short", []string{"Gato negro."}, ""},
+		{"too long", []string{"El gato de mi vecina duerme todas las tardes en el sofá de la sala."}, ""},
+		{"no examples", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := newTestContent("gato", "cat", tt.examples...)
+			if got := wordOrderSentence(content); got != tt.want {
+				t.Errorf("wordOrderSentence(%q) = %q, want %q", tt.examples, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildWordOrderQuiz(t *testing.T) {
+	content := newTestContent("gato", "cat", "El gato duerme en casa.")
+	quiz := buildWordOrderQuiz(content)
+	if quiz == nil {
+		t.Fatal("buildWordOrderQuiz() = nil")
+	}
+	if quiz.CorrectAnswer != "El gato duerme en casa" {
+		t.Errorf("correct answer = %q", quiz.CorrectAnswer)
+	}
+	options := append([]string{}, quiz.Options...)
+	sort.Strings(options)
+	if got, want := strings.Join(options, " "), "El casa duerme en gato"; got != want {
+		t.Errorf("options = %v, want the sentence's words", quiz.Options)
+	}
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"lexipath-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// quizTypesByBucket lists the quiz types for each mastery bucket, most
+// preferred first: recognition for new and weak words, recall for moderate
+// ones and production for strong ones.
+var quizTypesByBucket = map[models.MasteryBucket][]models.QuizType{
+	models.MasteryBucketNew:      {models.QuizTypeMCQ, models.QuizTypeMatching},
+	models.MasteryBucketWeak:     {models.QuizTypeMCQ, models.QuizTypeMatching},
+	models.MasteryBucketModerate: {models.QuizTypeFillBlank, models.QuizTypeWordOrder},
+	models.MasteryBucketStrong:   {models.QuizTypeSituation, models.QuizTypeSentence, models.QuizTypeReverseTranslation},
+}
+
+// easierBucket is the bucket whose quiz types are one step easier.
+var easierBucket = map[models.MasteryBucket]models.MasteryBucket{
+	models.MasteryBucketStrong:   models.MasteryBucketModerate,
+	models.MasteryBucketModerate: models.MasteryBucketWeak,
+}
+
+// recentQuizTypes is how many of a word's latest answers are looked at to
+// avoid asking the same type again.
+const recentQuizTypes = 2
+
+// distractorCloseness is how similar multiple choice distractors and the
+// other pairs of a matching question are to the word.
+type distractorCloseness int
+
+const (
+	distractorsFar distractorCloseness = iota
+	distractorsMixed
+	distractorsClose
+)
+
+// SelectQuizType picks the quiz type to ask the content with, from the
+// word's mastery and quiz history. previous is the type of the question
+// before it in a session, which isn't repeated when there is a choice.
+func (s *QuizService) SelectQuizType(ctx context.Context, userID uuid.UUID, content *models.DailyContent, previous models.QuizType) (models.QuizType, error) {
+	mastery, err := s.masteryRepo.GetByUserAndContent(ctx, userID, content.ID)
+	if err != nil {
+		return "", err
+	}
+	history, err := s.quizRepo.GetByUserAndContent(ctx, userID, content.ID)
+	if err != nil {
+		return "", err
+	}
+	return selectQuizType(content, mastery, history, previous), nil
+}
+
+// DistractorCloseness returns how close distractors should be for the
+// word: far while it is new, mixed while weak and close once the user
+// knows it moderately well.
+func (s *QuizService) DistractorCloseness(ctx context.Context, userID, contentID uuid.UUID) (distractorCloseness, error) {
+	mastery, err := s.masteryRepo.GetByUserAndContent(ctx, userID, contentID)
+	if err != nil {
+		return distractorsFar, err
+	}
+
+	switch masteryBucket(mastery) {
+	case models.MasteryBucketNew:
+		return distractorsFar, nil
+	case models.MasteryBucketWeak:
+		return distractorsMixed, nil
+	default:
+		return distractorsClose, nil
+	}
+}
+
+// selectQuizType picks a type from the word's mastery bucket. A wrong latest
+// answer steps down to the easier bucket. Within the bucket, types the
+// content can't support, that were used for the word's latest answers or
+// that were just asked are skipped, falling back to the bucket's first
+// supported type, and to an easier bucket when the content supports none.
+func selectQuizType(content *models.DailyContent, mastery *models.Mastery, history []*models.QuizLog, previous models.QuizType) models.QuizType {
+	bucket := masteryBucket(mastery)
+	if len(history) > 0 && !history[0].IsCorrect {
+		if easier, ok := easierBucket[bucket]; ok {
+			bucket = easier
+		}
+	}
+
+	recent := map[models.QuizType]bool{}
+	for _, quizLog := range history[:min(len(history), recentQuizTypes)] {
+		recent[quizLog.QuizType] = true
+	}
+
+	for {
+		var fallback models.QuizType
+		for _, quizType := range quizTypesByBucket[bucket] {
+			if plannedQuizType(content, quizType) != quizType {
+				continue
+			}
+			if fallback == "" {
+				fallback = quizType
+			}
+			if quizType != previous && !recent[quizType] {
+				return quizType
+			}
+		}
+		if fallback != "" {
+			return fallback
+		}
+
+		easier, ok := easierBucket[bucket]
+		if !ok {
+			return models.QuizTypeMCQ
+		}
+		bucket = easier
+	}
+}
+
+// masteryBucket groups a mastery score with the same thresholds as the
+// mastery stats.
+func masteryBucket(mastery *models.Mastery) models.MasteryBucket {
+	switch {
+	case mastery == nil:
+		return models.MasteryBucketNew
+	case mastery.MasteryScore < 50:
+		return models.MasteryBucketWeak
+	case mastery.MasteryScore < 80:
+		return models.MasteryBucketModerate
+	default:
+		return models.MasteryBucketStrong
+	}
+}
+
+// orderDistractors orders candidate distractors for the word by closeness:
+// most similar first for close, least similar first for far, and shuffled
+// for mixed. Ties are broken at random.
+func orderDistractors(content *models.DailyContent, candidates []*models.DailyContent, closeness distractorCloseness) []*models.DailyContent {
+	ordered := append([]*models.DailyContent{}, candidates...)
+	rand.Shuffle(len(ordered), func(i, j int) { ordered[i], ordered[j] = ordered[j], ordered[i] })
+	if closeness == distractorsMixed {
+		return ordered
+	}
+
+	scores := make(map[*models.DailyContent]int, len(ordered))
+	for _, candidate := range ordered {
+		scores[candidate] = distractorSimilarity(content, candidate)
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if closeness == distractorsClose {
+			return scores[ordered[i]] > scores[ordered[j]]
+		}
+		return scores[ordered[i]] < scores[ordered[j]]
+	})
+	return ordered
+}
+
+// distractorSimilarity scores how easily another word could be mistaken for
+// the word: the same part of speech and gender, a similar spelling, shared
+// tags and meanings that share words.
+func distractorSimilarity(content, other *models.DailyContent) int {
+	score := 0
+	if content.PartOfSpeech != "" && content.PartOfSpeech == other.PartOfSpeech {
+		score += 3
+		if content.Gender != "" && content.Gender == other.Gender {
+			score++
+		}
+	}
+
+	word, otherWord := foldAccents(normalizeAnswer(content.Word)), foldAccents(normalizeAnswer(other.Word))
+	if editDistance(word, otherWord) <= max(1, utf8.RuneCountInString(word)/3) {
+		score += 2
+	}
+
+	for _, tag := range content.Tags {
+		if slices.Contains(other.Tags, tag) {
+			score++
+			break
+		}
+	}
+
+	meaningWords := map[string]bool{}
+	for _, word := range strings.Fields(normalizeAnswer(content.Meaning)) {
+		// Short words are mostly articles and particles such as "to"
+		if utf8.RuneCountInString(word) >= 4 {
+			meaningWords[word] = true
+		}
+	}
+	for _, word := range strings.Fields(normalizeAnswer(other.Meaning)) {
+		if meaningWords[word] {
+			score += 2
+			break
+		}
+	}
+
+	return score
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"testing"
+
+	"lexipath-backend/internal/models"
+)
+
+func TestMasteryBucket(t *testing.T) {
+	tests := []struct {
+		name    string
+		mastery *models.Mastery
+		want    models.MasteryBucket
+	}{
+		{"no mastery yet", nil, models.MasteryBucketNew},
+		{"zero", &models.Mastery{MasteryScore: 0}, models.MasteryBucketWeak},
+		{"just below moderate", &models.Mastery{MasteryScore: 49}, models.MasteryBucketWeak},
+		{"moderate", &models.Mastery{MasteryScore: 50}, models.MasteryBucketModerate},
+		{"just below strong", &models.Mastery{MasteryScore: 79}, models.MasteryBucketModerate},
+		{"strong", &models.Mastery{MasteryScore: 80}, models.MasteryBucketStrong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := masteryBucket(tt.mastery); got != tt.want {
+				t.Errorf("masteryBucket() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectQuizType(t *testing.T) {
+	withExamples := newTestContent("gato", "cat", "El gato duerme en casa.")
+	withExamples.ExamplesBase = []string{"The cat sleeps at home."}
+	bare := newTestContent("gato", "cat")
+
+	weak := &models.Mastery{MasteryScore: 30}
+	moderate := &models.Mastery{MasteryScore: 60}
+	strong := &models.Mastery{MasteryScore: 90}
+
+	answered := func(quizType models.QuizType, correct bool) *models.QuizLog {
+		return &models.QuizLog{QuizType: quizType, IsCorrect: correct}
+	}
+
+	tests := []struct {
+		name     string
+		content  *models.DailyContent
+		mastery  *models.Mastery
+		history  []*models.QuizLog
+		previous models.QuizType
+		want     models.QuizType
+	}{
+		{"new word", withExamples, nil, nil, "", models.QuizTypeMCQ},
+		{"weak word", withExamples, weak, nil, "", models.QuizTypeMCQ},
+		{"weak word after multiple choice", withExamples, weak, nil, models.QuizTypeMCQ, models.QuizTypeMatching},
+		{"moderate word", withExamples, moderate, nil, "", models.QuizTypeFillBlank},
+		{"moderate word recently filled in", withExamples, moderate, []*models.QuizLog{answered(models.QuizTypeFillBlank, true)}, "", models.QuizTypeWordOrder},
+		{"strong word", withExamples, strong, nil, "", models.QuizTypeSituation},
+		{"strong word recently asked", withExamples, strong, []*models.QuizLog{answered(models.QuizTypeSituation, true), answered(models.QuizTypeSentence, true)}, "", models.QuizTypeReverseTranslation},
+		{"only recent answers count", withExamples, strong, []*models.QuizLog{answered(models.QuizTypeSituation, true), answered(models.QuizTypeMCQ, true), answered(models.QuizTypeSentence, true)}, "", models.QuizTypeSentence},
+		{"wrong answer steps down from strong", withExamples, strong, []*models.QuizLog{answered(models.QuizTypeSituation, false)}, "", models.QuizTypeFillBlank},
+		{"wrong answer steps down from moderate", withExamples, moderate, []*models.QuizLog{answered(models.QuizTypeFillBlank, false)}, "", models.QuizTypeMCQ},
+		{"only the latest answer steps down", withExamples, strong, []*models.QuizLog{answered(models.QuizTypeSentence, true), answered(models.QuizTypeSituation, false)}, "", models.QuizTypeReverseTranslation},
+		{"wrong answer on a new word", withExamples, nil, []*models.QuizLog{answered(models.QuizTypeMCQ, false)}, "", models.QuizTypeMatching},
+		{"every type used falls back to the first", withExamples, weak, []*models.QuizLog{answered(models.QuizTypeMatching, true)}, models.QuizTypeMCQ, models.QuizTypeMCQ},
+		{"unsupported types step down", bare, moderate, nil, "", models.QuizTypeMCQ},
+		{"strong word without examples", bare, strong, nil, "", models.QuizTypeSituation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selectQuizType(tt.content, tt.mastery, tt.history, tt.previous); got != tt.want {
+				t.Errorf("selectQuizType() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,8 +3,11 @@ package services
 import (
 	"context"
 	"fmt"
+	"math"
+	"strings"
 	"time"
 
+	"lexipath-backend/internal/config"
 	"lexipath-backend/internal/models"
 	"lexipath-backend/internal/repositories"
 
@@ -13,70 +16,139 @@ import (
 )
 
 type QuizService struct {
-	quizRepo    *repositories.QuizRepository
-	masteryRepo *repositories.MasteryRepository
-	logger      *zap.Logger
+	quizRepo      *repositories.QuizRepository
+	masteryRepo   *repositories.MasteryRepository
+	contentRepo   *repositories.ContentRepository
+	cacheRepo     *repositories.CacheRepository
+	geminiService *GeminiService
+	grading       config.GradingConfig
+	logger        *zap.Logger
 }
 
-func NewQuizService(quizRepo *repositories.QuizRepository, masteryRepo *repositories.MasteryRepository, logger *zap.Logger) *QuizService {
+func NewQuizService(quizRepo *repositories.QuizRepository, masteryRepo *repositories.MasteryRepository, contentRepo *repositories.ContentRepository, cacheRepo *repositories.CacheRepository, geminiService *GeminiService, grading config.GradingConfig, logger *zap.Logger) *QuizService {
 	return &QuizService{
-		quizRepo:    quizRepo,
-		masteryRepo: masteryRepo,
-		logger:      logger,
+		quizRepo:      quizRepo,
+		masteryRepo:   masteryRepo,
+		contentRepo:   contentRepo,
+		cacheRepo:     cacheRepo,
+		geminiService: geminiService,
+		grading:       grading,
+		logger:        logger,
 	}
 }
 
-func (s *QuizService) SubmitQuiz(ctx context.Context, userID uuid.UUID, req *models.QuizSubmissionRequest, correctAnswer string, question string, options []string) (*models.QuizLog, error) {
-	// Determine if answer is correct
-	isCorrect := req.UserAnswer == correctAnswer
+// MasteryChange is how an answer moved a word's mastery.
+type MasteryChange struct {
+	// Before is nil for the first answer on a word.
+	Before         *int
+	After          int
+	NextReviewDate *time.Time
+}
+
+// GradeAnswer grades the quiz log's answer in the profile's target language
+// and sets its IsCorrect, Grade, Feedback and Rubric, and the Explanation of
+// a wrong answer. Only exact answers are correct. Free-text answers that
+// don't match the reference answer are scored by the LLM against a rubric;
+// if it can't score them, the error is returned and nothing is graded, so
+// the answer can be submitted again.
+func (s *QuizService) GradeAnswer(ctx context.Context, profile *models.Profile, quizLog *models.QuizLog) error {
+	var result GradeResult
+	switch quizLog.QuizType {
+	case models.QuizTypeMatching:
+		result = gradeMatching(quizLog.Terms, quizLog.CorrectAnswer, quizLog.UserAnswer)
+	case models.QuizTypeWordOrder:
+		result = gradeWordOrder(quizLog.CorrectAnswer, quizLog.UserAnswer)
+	default:
+		result = gradeAnswer(s.grading, quizLog.QuizType, quizLog.CorrectAnswer, quizLog.UserAnswer, lexicalLanguage(profile))
+	}
+
+	if quizLog.QuizType.IsFreeText() && result.Grade != models.AnswerGradeExact && strings.TrimSpace(quizLog.UserAnswer) != "" {
+		content, err := s.contentRepo.GetByID(ctx, profile.UserID, quizLog.ContentID)
+		if err != nil {
+			return err
+		}
+		if content == nil {
+			return ErrContentNotFound
+		}
 
+		// The local grade only compares the answer to one reference sentence,
+		// so it can't stand in for the rubric when Gemini is unavailable
+		rubricResp, err := s.geminiService.GradeFreeText(ctx, profile, content, quizLog.Question, quizLog.CorrectAnswer, quizLog.UserAnswer)
+		if err != nil {
+			return err
+		}
+		result = scoreRubric(rubricResp)
+	}
+
+	quizLog.IsCorrect = result.Correct()
+	quizLog.Grade = result.Grade
+	quizLog.Feedback = result.Feedback
+	quizLog.Rubric = result.Rubric
+
+	// Close answers already have feedback on what to fix
+	if quizLog.Grade == models.AnswerGradeWrong {
+		s.explainAnswer(ctx, profile, quizLog)
+	}
+	return nil
+}
+
+// RecordAnswer logs a graded answer and updates the word's mastery. The
+// mastery change is nil if the update failed, which doesn't fail the answer.
+func (s *QuizService) RecordAnswer(ctx context.Context, quizLog *models.QuizLog) (*MasteryChange, error) {
 	// Create quiz log
-	quizLog, err := s.quizRepo.Create(ctx, userID, req.ContentID, req.QuizType, question, options, correctAnswer, req.UserAnswer, isCorrect)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create quiz log: %w", err)
+	if err := s.quizRepo.Create(ctx, quizLog); err != nil {
+		return nil, err
 	}
 
 	// Update mastery score
-	if err := s.updateMastery(ctx, userID, req.ContentID, isCorrect); err != nil {
+	change, err := s.updateMastery(ctx, quizLog.UserID, quizLog.ContentID, answerCredit(quizLog))
+	if err != nil {
 		s.logger.Error("Failed to update mastery", zap.Error(err))
 		// Don't fail the entire request if mastery update fails
 	}
 
-	return quizLog, nil
+	return change, nil
 }
 
-func (s *QuizService) updateMastery(ctx context.Context, userID, contentID uuid.UUID, isCorrect bool) error {
+// updateMastery moves the word's mastery by the answer's credit, from 0 for
+// a wrong answer to 1 for a correct one. Partial credit blends the increase
+// for a correct answer with the decrease for a wrong one.
+func (s *QuizService) updateMastery(ctx context.Context, userID, contentID uuid.UUID, credit float64) (*MasteryChange, error) {
 	// Get current mastery
 	mastery, err := s.masteryRepo.GetByUserAndContent(ctx, userID, contentID)
 	if err != nil {
-		return fmt.Errorf("failed to get mastery: %w", err)
+		return nil, fmt.Errorf("failed to get mastery: %w", err)
 	}
 
+	change := &MasteryChange{}
 	var newScore int
 	if mastery == nil {
-		// First attempt
-		if isCorrect {
-			newScore = 60 // Start with 60% for correct first attempt
-		} else {
-			newScore = 20 // Start with 20% for incorrect first attempt
-		}
+		// First attempt: start at 60% for a correct answer, 20% for an
+		// incorrect one
+		newScore = 20 + int(math.Round(40*credit))
 	} else {
+		before := mastery.MasteryScore
+		change.Before = &before
 		// Update existing score
-		if isCorrect {
-			// Increase score by 15-25 points based on current score
-			increase := 25 - (mastery.MasteryScore / 5)
-			if increase < 15 {
-				increase = 15
-			}
-			newScore = mastery.MasteryScore + increase
-		} else {
-			// Decrease score by 10-20 points
-			decrease := 10 + (mastery.MasteryScore / 10)
-			if decrease > 20 {
-				decrease = 20
-			}
-			newScore = mastery.MasteryScore - decrease
+		// Increase score by 15-25 points based on current score
+		increase := 25 - (mastery.MasteryScore / 5)
+		if increase < 15 {
+			increase = 15
+		}
+		// Decrease score by 10-20 points
+		decrease := 10 + (mastery.MasteryScore / 10)
+		if decrease > 20 {
+			decrease = 20
 		}
+		delta := credit*float64(increase) - (1-credit)*float64(decrease)
+		newScore = mastery.MasteryScore + int(math.Round(delta))
+	}
+
+	// Keep within the 0-100 range the mastery table allows
+	if newScore > 100 {
+		newScore = 100
+	} else if newScore < 0 {
+		newScore = 0
 	}
 
 	// Calculate next review date based on mastery score
@@ -98,8 +170,10 @@ func (s *QuizService) updateMastery(ctx context.Context, userID, contentID uuid.
 	// Update mastery
 	_, err = s.masteryRepo.Upsert(ctx, userID, contentID, newScore, nextReviewDate)
 	if err != nil {
-		return fmt.Errorf("failed to update mastery: %w", err)
+		return nil, fmt.Errorf("failed to update mastery: %w", err)
 	}
 
-	return nil
+	change.After = newScore
+	change.NextReviewDate = nextReviewDate
+	return change, nil
 }
@@ -0,0 +1,478 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"lexipath-backend/internal/config"
+	"lexipath-backend/internal/models"
+	"lexipath-backend/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Bounds for the number of questions in a review session.
+const (
+	defaultReviewSessionSize = 10
+	maxReviewSessionSize     = 30
+)
+
+var (
+	// ErrInvalidReviewSession is returned for a session request that can't be started.
+	ErrInvalidReviewSession = errors.New("invalid review session")
+	// ErrNothingToReview is returned when the user has no words to quiz.
+	ErrNothingToReview = errors.New("nothing to review")
+	// ErrReviewSessionNotFound is returned when a session doesn't exist for the user.
+	ErrReviewSessionNotFound = errors.New("review session not found")
+	// ErrReviewSessionConflict is returned for a step the session isn't ready
+	// for, such as answering a finished session or a question not yet asked.
+	ErrReviewSessionConflict = errors.New("review session conflict")
+	// ErrQuizQuestionNotFound is returned for a quiz answer that doesn't
+	// answer a stored question.
+	ErrQuizQuestionNotFound = errors.New("quiz question not found")
+)
+
+// otherWordsPool is how many of the user's recent words are considered for
+// multiple choice distractors and the other pairs of a matching question.
+const otherWordsPool = 2 * maxMatchingPairs
+
+type ReviewSessionService struct {
+	sessionRepo   *repositories.ReviewSessionRepository
+	contentRepo   *repositories.ContentRepository
+	quizService   *QuizService
+	geminiService *GeminiService
+	quizConfig    config.QuizConfig
+	location      *time.Location
+	logger        *zap.Logger
+}
+
+func NewReviewSessionService(
+	sessionRepo *repositories.ReviewSessionRepository,
+	contentRepo *repositories.ContentRepository,
+	quizService *QuizService,
+	geminiService *GeminiService,
+	quizConfig config.QuizConfig,
+	location *time.Location,
+	logger *zap.Logger,
+) *ReviewSessionService {
+	return &ReviewSessionService{
+		sessionRepo:   sessionRepo,
+		contentRepo:   contentRepo,
+		quizService:   quizService,
+		geminiService: geminiService,
+		quizConfig:    quizConfig,
+		location:      location,
+		logger:        logger,
+	}
+}
+
+// StartSession starts a session over the user's due reviews and weak words
+// as of the date, today in the profile's timezone by default, replacing any
+// session in progress.
+func (s *ReviewSessionService) StartSession(ctx context.Context, profile *models.Profile, req *models.StartReviewSessionRequest) (*models.ReviewSession, error) {
+	userID := profile.UserID
+
+	size := req.Size
+	if size == 0 {
+		size = defaultReviewSessionSize
+	}
+	if size < 1 || size > maxReviewSessionSize {
+		return nil, fmt.Errorf("%w: size must be between 1 and %d", ErrInvalidReviewSession, maxReviewSessionSize)
+	}
+
+	now := localNow(profile, s.location)
+	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if req.Date != "" {
+		parsed, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			return nil, fmt.Errorf("%w: date must be in YYYY-MM-DD format", ErrInvalidReviewSession)
+		}
+		date = parsed
+	}
+
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidReviewSession, err)
+	}
+
+	contents, err := s.contentRepo.ListForReview(ctx, userID, date, tags, size)
+	if err != nil {
+		return nil, err
+	}
+	if len(contents) == 0 {
+		return nil, ErrNothingToReview
+	}
+
+	session := &models.ReviewSession{UserID: userID, Tags: tags}
+	var previous models.QuizType
+	for i, content := range contents {
+		quizType, err := s.quizService.SelectQuizType(ctx, userID, content, previous)
+		if err != nil {
+			return nil, err
+		}
+		session.Items = append(session.Items, &models.ReviewSessionItem{
+			Position:  i + 1,
+			ContentID: content.ID,
+			QuizType:  quizType,
+		})
+		previous = quizType
+	}
+
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Started review session",
+		zap.String("user_id", userID.String()),
+		zap.String("session_id", session.ID.String()),
+		zap.Int("items", len(session.Items)))
+
+	return prepareSession(session), nil
+}
+
+func (s *ReviewSessionService) GetSession(ctx context.Context, userID, sessionID uuid.UUID) (*models.ReviewSession, error) {
+	session, err := s.sessionRepo.GetByID(ctx, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, ErrReviewSessionNotFound
+	}
+	return prepareSession(session), nil
+}
+
+// GetActiveSession returns the user's session in progress, so it can be
+// resumed, or nil if there is none.
+func (s *ReviewSessionService) GetActiveSession(ctx context.Context, userID uuid.UUID) (*models.ReviewSession, error) {
+	session, err := s.sessionRepo.GetActive(ctx, userID)
+	if err != nil || session == nil {
+		return nil, err
+	}
+	return prepareSession(session), nil
+}
+
+// GetQuestion returns the session's next unanswered question, generating it
+// when first asked.
+func (s *ReviewSessionService) GetQuestion(ctx context.Context, userID, sessionID uuid.UUID) (*models.ReviewQuestion, error) {
+	session, item, err := s.currentItem(ctx, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if item.Question == "" {
+		item, err = s.generateQuestion(ctx, userID, item)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.ReviewQuestion{
+		SessionID: session.ID,
+		Position:  item.Position,
+		Total:     len(session.Items),
+		ContentID: item.ContentID,
+		QuizType:  item.QuizType,
+		Question:  item.Question,
+		Options:   item.Options,
+		Terms:     item.Terms,
+	}, nil
+}
+
+// SubmitAnswer grades the answer to the session's current question and
+// updates the word's mastery. Answering the last question completes the
+// session.
+func (s *ReviewSessionService) SubmitAnswer(ctx context.Context, profile *models.Profile, sessionID uuid.UUID, answer string) (*models.ReviewAnswerResult, error) {
+	userID := profile.UserID
+	session, item, err := s.currentItem(ctx, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if item.Question == "" {
+		return nil, fmt.Errorf("%w: question %d hasn't been asked yet", ErrReviewSessionConflict, item.Position)
+	}
+
+	// Claim the question before grading, so a repeated or retried submit
+	// isn't graded, and charged for, twice
+	answeredAt := time.Now().UTC()
+	item.UserAnswer = &answer
+	item.AnsweredAt = &answeredAt
+	claimed, err := s.sessionRepo.ClaimAnswer(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, fmt.Errorf("%w: question %d was already answered", ErrReviewSessionConflict, item.Position)
+	}
+
+	quizLog := &models.QuizLog{
+		UserID:        userID,
+		ContentID:     item.ContentID,
+		QuizType:      item.QuizType,
+		Question:      item.Question,
+		Options:       item.Options,
+		Terms:         item.Terms,
+		CorrectAnswer: item.CorrectAnswer,
+		UserAnswer:    answer,
+	}
+	if err := s.quizService.GradeAnswer(ctx, profile, quizLog); err != nil {
+		s.releaseAnswer(ctx, item)
+		return nil, err
+	}
+
+	item.IsCorrect = &quizLog.IsCorrect
+	item.Grade = quizLog.Grade
+	item.Feedback = quizLog.Feedback
+	item.Rubric = quizLog.Rubric
+
+	change, err := s.quizService.RecordAnswer(ctx, quizLog)
+	if err != nil {
+		s.releaseAnswer(ctx, item)
+		return nil, err
+	}
+
+	item.QuizLogID = &quizLog.ID
+	if change != nil {
+		item.MasteryBefore = change.Before
+		item.MasteryAfter = &change.After
+		item.NextReviewDate = change.NextReviewDate
+	}
+	if err := s.sessionRepo.SetAnswerResult(ctx, item); err != nil {
+		s.logger.Warn("Failed to store review answer result", zap.Error(err), zap.String("session_id", sessionID.String()))
+	}
+
+	result := &models.ReviewAnswerResult{Item: item, QuizLog: quizLog}
+	if item.Position < len(session.Items) {
+		return result, nil
+	}
+
+	completed, err := s.FinishSession(ctx, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	result.Completed = true
+	result.Session = completed
+	return result, nil
+}
+
+// SubmitQuiz grades an answer to the current question of the user's active
+// review session, which must be for the requested word and quiz type. The
+// question and correct answer are never taken from the client, so an answer
+// to a question that isn't stored is rejected rather than graded.
+func (s *ReviewSessionService) SubmitQuiz(ctx context.Context, profile *models.Profile, req *models.QuizSubmissionRequest) (*models.QuizLog, error) {
+	session, err := s.sessionRepo.GetActive(ctx, profile.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, ErrQuizQuestionNotFound
+	}
+
+	for _, item := range session.Items {
+		if item.AnsweredAt != nil {
+			continue
+		}
+		if item.ContentID != req.ContentID || item.QuizType != req.QuizType || item.Question == "" {
+			break
+		}
+		result, err := s.SubmitAnswer(ctx, profile, session.ID, req.UserAnswer)
+		if err != nil {
+			return nil, err
+		}
+		return result.QuizLog, nil
+	}
+	return nil, ErrQuizQuestionNotFound
+}
+
+// releaseAnswer lets the user answer an item again after its answer
+// couldn't be graded or recorded.
+func (s *ReviewSessionService) releaseAnswer(ctx context.Context, item *models.ReviewSessionItem) {
+	if err := s.sessionRepo.ReleaseAnswer(ctx, item.ID); err != nil {
+		s.logger.Warn("Failed to release review answer", zap.Error(err), zap.String("item_id", item.ID.String()))
+	}
+}
+
+// FinishSession completes the session, leaving any unanswered questions
+// unanswered, and returns it with its summary.
+func (s *ReviewSessionService) FinishSession(ctx context.Context, userID, sessionID uuid.UUID) (*models.ReviewSession, error) {
+	session, err := s.sessionRepo.GetByID(ctx, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, ErrReviewSessionNotFound
+	}
+	if session.Status != models.ReviewSessionStatusActive {
+		return nil, fmt.Errorf("%w: session is %s", ErrReviewSessionConflict, session.Status)
+	}
+
+	if _, err := s.sessionRepo.Complete(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	completedAt := time.Now().UTC()
+	session.Status = models.ReviewSessionStatusCompleted
+	session.CompletedAt = &completedAt
+	return prepareSession(session), nil
+}
+
+// currentItem loads an active session and its first unanswered item.
+func (s *ReviewSessionService) currentItem(ctx context.Context, userID, sessionID uuid.UUID) (*models.ReviewSession, *models.ReviewSessionItem, error) {
+	session, err := s.sessionRepo.GetByID(ctx, userID, sessionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if session == nil {
+		return nil, nil, ErrReviewSessionNotFound
+	}
+	if session.Status != models.ReviewSessionStatusActive {
+		return nil, nil, fmt.Errorf("%w: session is %s", ErrReviewSessionConflict, session.Status)
+	}
+
+	for _, item := range session.Items {
+		if item.AnsweredAt == nil {
+			return session, item, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("%w: every question has been answered", ErrReviewSessionConflict)
+}
+
+// generateQuestion builds the item's question. With the local generator,
+// every type but situation is built from the user's content, falling back to
+// a local multiple choice question when the planned type can't be built.
+// The LLM generates situation questions, questions the local generator
+// can't build at all, and, with the llm generator, the types it has prompts
+// for.
+func (s *ReviewSessionService) generateQuestion(ctx context.Context, userID uuid.UUID, item *models.ReviewSessionItem) (*models.ReviewSessionItem, error) {
+	content, err := s.contentRepo.GetByID(ctx, userID, item.ContentID)
+	if err != nil {
+		return nil, err
+	}
+	if content == nil {
+		return nil, ErrContentNotFound
+	}
+
+	quizType := item.QuizType
+	var quiz *models.GeneratedQuiz
+	if s.usesLocalGenerator(quizType) {
+		if quiz, err = s.buildLocalQuiz(ctx, userID, content, quizType); err != nil {
+			return nil, err
+		}
+		if quiz == nil && quizType != models.QuizTypeMCQ && s.usesLocalGenerator(models.QuizTypeMCQ) {
+			if quiz, err = s.buildLocalQuiz(ctx, userID, content, models.QuizTypeMCQ); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if quiz == nil {
+		// Only these types have LLM prompts
+		if quizType != models.QuizTypeFillBlank && quizType != models.QuizTypeSituation && quizType != models.QuizTypeSentence {
+			quizType = models.QuizTypeMCQ
+		}
+
+		generated, err := s.geminiService.GenerateQuiz(ctx, content, quizType)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(generated.Question) == "" || strings.TrimSpace(generated.CorrectAnswer) == "" {
+			return nil, fmt.Errorf("generated %s question is incomplete", quizType)
+		}
+
+		// The prompt lists the correct option first
+		options := append([]string{}, generated.Options...)
+		rand.Shuffle(len(options), func(i, j int) { options[i], options[j] = options[j], options[i] })
+
+		quiz = &models.GeneratedQuiz{
+			QuizType:      quizType,
+			Question:      generated.Question,
+			Options:       options,
+			CorrectAnswer: generated.CorrectAnswer,
+		}
+	}
+
+	return s.sessionRepo.SetQuestion(ctx, item.ID, quiz)
+}
+
+// usesLocalGenerator reports whether questions of the type are built from
+// the user's content rather than by the LLM.
+func (s *ReviewSessionService) usesLocalGenerator(quizType models.QuizType) bool {
+	switch quizType {
+	case models.QuizTypeSituation:
+		return false
+	case models.QuizTypeMCQ, models.QuizTypeFillBlank, models.QuizTypeSentence:
+		return s.quizConfig.Generator != QuizGeneratorLLM
+	default:
+		// The LLM has no prompts for the other types
+		return true
+	}
+}
+
+// buildLocalQuiz builds a question of the type from the user's content, or
+// returns nil if the content doesn't support one.
+func (s *ReviewSessionService) buildLocalQuiz(ctx context.Context, userID uuid.UUID, content *models.DailyContent, quizType models.QuizType) (*models.GeneratedQuiz, error) {
+	switch quizType {
+	case models.QuizTypeMCQ, models.QuizTypeMatching:
+		others, err := s.contentRepo.ListRecent(ctx, userID, content.ID, otherWordsPool)
+		if err != nil {
+			return nil, err
+		}
+		closeness, err := s.quizService.DistractorCloseness(ctx, userID, content.ID)
+		if err != nil {
+			return nil, err
+		}
+		if quizType == models.QuizTypeMatching {
+			return buildMatchingQuiz(content, others, closeness), nil
+		}
+		return buildMCQQuiz(content, others, closeness), nil
+	case models.QuizTypeFillBlank:
+		return buildFillBlankQuiz(content), nil
+	case models.QuizTypeSentence:
+		return buildSentenceQuiz(content), nil
+	case models.QuizTypeWordOrder:
+		return buildWordOrderQuiz(content), nil
+	case models.QuizTypeReverseTranslation:
+		return buildReverseTranslationQuiz(content), nil
+	default:
+		return nil, nil
+	}
+}
+
+// prepareSession hides the answers of unanswered items and adds the summary.
+func prepareSession(session *models.ReviewSession) *models.ReviewSession {
+	summary := &models.ReviewSessionSummary{Total: len(session.Items)}
+	for _, item := range session.Items {
+		if item.AnsweredAt == nil {
+			item.CorrectAnswer = ""
+			continue
+		}
+
+		summary.Answered++
+		if item.IsCorrect != nil && *item.IsCorrect {
+			summary.Correct++
+		}
+		if item.Grade == models.AnswerGradeClose {
+			summary.Close++
+		}
+		if item.MasteryAfter != nil {
+			before := 0
+			if item.MasteryBefore != nil {
+				before = *item.MasteryBefore
+			}
+			summary.MasteryDelta += *item.MasteryAfter - before
+		}
+		if item.NextReviewDate != nil && (summary.NextReviewDate == nil || item.NextReviewDate.Before(*summary.NextReviewDate)) {
+			summary.NextReviewDate = item.NextReviewDate
+		}
+	}
+	if summary.Answered > 0 {
+		summary.Accuracy = float64(summary.Correct) / float64(summary.Answered)
+	}
+
+	session.Summary = summary
+	return session
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"lexipath-backend/internal/models"
+	"lexipath-backend/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ErrUsageBudgetExceeded is returned before an LLM call when the user has
+// used up their daily or monthly token budget.
+var ErrUsageBudgetExceeded = errors.New("LLM usage budget exceeded")
+
+type UsageService struct {
+	usageRepo *repositories.UsageRepository
+	location  *time.Location
+	logger    *zap.Logger
+}
+
+func NewUsageService(usageRepo *repositories.UsageRepository, location *time.Location, logger *zap.Logger) *UsageService {
+	return &UsageService{
+		usageRepo: usageRepo,
+		location:  location,
+		logger:    logger,
+	}
+}
+
+// CheckBudget returns ErrUsageBudgetExceeded if the user has no tokens left
+// for today or for this month. Calls made on behalf of no user are not limited.
+func (s *UsageService) CheckBudget(ctx context.Context, userID uuid.UUID) error {
+	if userID == uuid.Nil {
+		return nil
+	}
+
+	budget, err := s.usageRepo.GetBudgetForUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get usage budget: %w", err)
+	}
+	if budget == nil {
+		return nil
+	}
+
+	now := time.Now().In(s.location)
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.location)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, s.location)
+
+	daily, monthly, err := s.usageRepo.GetUserTokenTotals(ctx, userID, dayStart, monthStart)
+	if err != nil {
+		return fmt.Errorf("failed to get token usage: %w", err)
+	}
+
+	if daily >= budget.DailyTokenLimit || monthly >= budget.MonthlyTokenLimit {
+		s.logger.Info("LLM usage budget exceeded",
+			zap.String("user_id", userID.String()),
+			zap.Int("daily_tokens", daily),
+			zap.Int("monthly_tokens", monthly))
+		return ErrUsageBudgetExceeded
+	}
+
+	return nil
+}
+
+// Record appends a call to the usage ledger. A nil userID records usage that
+// isn't attributable to a single user.
+func (s *UsageService) Record(ctx context.Context, userID uuid.UUID, feature, model string, usage GeminiUsageMetadata) {
+	entry := &models.LLMUsage{
+		Feature:          feature,
+		Model:            model,
+		PromptTokens:     usage.PromptTokenCount,
+		CandidatesTokens: usage.CandidatesTokenCount,
+		TotalTokens:      usage.TotalTokenCount,
+	}
+	if userID != uuid.Nil {
+		entry.UserID = &userID
+	}
+
+	if err := s.usageRepo.Create(ctx, entry); err != nil {
+		s.logger.Warn("Failed to record LLM usage", zap.Error(err), zap.String("feature", feature))
+	}
+}
+
+func (s *UsageService) GetSummary(ctx context.Context, from, to time.Time, groupBy []string) ([]*models.UsageSummary, error) {
+	return s.usageRepo.GetSummary(ctx, from, to, groupBy)
+}
+
+func (s *UsageService) SetBudget(ctx context.Context, req *models.SetUsageBudgetRequest) (*models.UsageBudget, error) {
+	return s.usageRepo.UpsertBudget(ctx, req)
+}
@@ -0,0 +1,50 @@
+// Package wordlists bundles small curated word lists that are served when
+// daily content can't be generated, so learners still get a word while
+// Gemini is unavailable.
+package wordlists
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+
+	"lexipath-backend/internal/models"
+)
+
+// Lists live in data/<target>-<base>/<level>.json for language pairs and in
+// data/industry/<level>.json for industry tracks.
+//
+//go:embed data
+var files embed.FS
+
+// ForLanguage returns the bundled list for a language pair and level, or nil
+// when none is bundled.
+func ForLanguage(targetLang, baseLang string, level models.LanguageLevel) ([]models.GeminiDailyContentResponse, error) {
+	return load(path.Join("data", targetLang+"-"+baseLang, string(level)+".json"))
+}
+
+// ForIndustry returns the bundled general professional vocabulary list for a
+// level, or nil when none is bundled.
+func ForIndustry(level models.LanguageLevel) ([]models.GeminiDailyContentResponse, error) {
+	return load(path.Join("data", "industry", string(level)+".json"))
+}
+
+func load(name string) ([]models.GeminiDailyContentResponse, error) {
+	data, err := files.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read word list %s: %w", name, err)
+	}
+
+	var entries []models.GeminiDailyContentResponse
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse word list %s: %w", name, err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,163 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"lexipath-backend/internal/config"
+	"lexipath-backend/internal/models"
+	"lexipath-backend/internal/repositories"
+	"lexipath-backend/internal/services"
+
+	"go.uber.org/zap"
+)
+
+// PregenerationWorker generates the next day's daily content for active users
+// shortly before their local midnight, so the morning request is served from
+// the cache or database instead of waiting on Gemini.
+type PregenerationWorker struct {
+	profileRepo     *repositories.ProfileRepository
+	contentService  *services.ContentService
+	config          config.PregenerationConfig
+	defaultLocation *time.Location
+	logger          *zap.Logger
+}
+
+func NewPregenerationWorker(profileRepo *repositories.ProfileRepository, contentService *services.ContentService, cfg config.PregenerationConfig, defaultLocation *time.Location, logger *zap.Logger) *PregenerationWorker {
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
+	if cfg.MaxPerMinute < 1 {
+		cfg.MaxPerMinute = 1
+	}
+	if cfg.Interval < time.Minute {
+		cfg.Interval = time.Minute
+	}
+
+	return &PregenerationWorker{
+		profileRepo:     profileRepo,
+		contentService:  contentService,
+		config:          cfg,
+		defaultLocation: defaultLocation,
+		logger:          logger,
+	}
+}
+
+// Run checks for due users every interval until ctx is cancelled.
+func (w *PregenerationWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	w.logger.Info("Pregeneration worker started",
+		zap.Duration("interval", w.config.Interval),
+		zap.Duration("lead_time", w.config.LeadTime))
+
+	for {
+		w.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			w.logger.Info("Pregeneration worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *PregenerationWorker) runOnce(ctx context.Context) {
+	now := time.Now()
+
+	profiles, err := w.profileRepo.ListActive(ctx, now.Add(-w.config.ActiveWindow))
+	if err != nil {
+		w.logger.Error("Failed to list active profiles", zap.Error(err))
+		return
+	}
+
+	// Stop the whole cycle as soon as Gemini reports it is rate limiting us
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Spread calls out so a cycle never exceeds MaxPerMinute generations
+	throttle := time.NewTicker(time.Minute / time.Duration(w.config.MaxPerMinute))
+	defer throttle.Stop()
+
+	sem := make(chan struct{}, w.config.Concurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	generated := 0
+
+	for _, profile := range profiles {
+		date, due := w.nextDate(profile, now)
+		if !due {
+			continue
+		}
+
+		// Skip users whose set was completed in an earlier cycle without using a throttle slot
+		exists, err := w.contentService.HasDailyContent(ctx, profile, date)
+		if err != nil {
+			w.logger.Warn("Failed to check existing content", zap.Error(err), zap.String("user_id", profile.UserID.String()))
+			continue
+		}
+		if exists {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-throttle.C:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(profile *models.Profile, date time.Time) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			created, err := w.contentService.PregenerateDailyContent(ctx, profile, date)
+			switch {
+			case errors.Is(err, services.ErrGeminiRateLimited):
+				w.logger.Warn("Gemini rate limited, stopping pregeneration cycle")
+				cancel()
+			case errors.Is(err, services.ErrUsageBudgetExceeded):
+				w.logger.Info("Skipping pregeneration, user is over budget", zap.String("user_id", profile.UserID.String()))
+			case err != nil && ctx.Err() == nil:
+				w.logger.Warn("Failed to pregenerate daily content", zap.Error(err), zap.String("user_id", profile.UserID.String()))
+			case created:
+				mu.Lock()
+				generated++
+				mu.Unlock()
+			}
+		}(profile, date)
+	}
+
+	wg.Wait()
+
+	if generated > 0 {
+		w.logger.Info("Pregenerated daily content", zap.Int("count", generated))
+	}
+}
+
+// nextDate returns the user's next local date, and whether their local
+// midnight is within the lead time so it is due for pregeneration.
+func (w *PregenerationWorker) nextDate(profile *models.Profile, now time.Time) (time.Time, bool) {
+	location := w.defaultLocation
+	if profile.Timezone != nil && *profile.Timezone != "" {
+		if loc, err := time.LoadLocation(*profile.Timezone); err == nil {
+			location = loc
+		}
+	}
+
+	local := now.In(location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, location)
+	if midnight.Sub(local) > w.config.LeadTime {
+		return time.Time{}, false
+	}
+
+	// Dates are stored without a time of day, keyed like client requests
+	return time.Date(midnight.Year(), midnight.Month(), midnight.Day(), 0, 0, 0, 0, time.UTC), true
+}
@@ -15,6 +15,7 @@ import (
 	"lexipath-backend/internal/middleware"
 	"lexipath-backend/internal/repositories"
 	"lexipath-backend/internal/services"
+	"lexipath-backend/internal/workers"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-contrib/gzip"
@@ -36,6 +37,11 @@ func main() {
 		logger.Fatal("Failed to load configuration", zap.Error(err))
 	}
 
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		logger.Fatal("Failed to load timezone", zap.Error(err), zap.String("timezone", cfg.Timezone))
+	}
+
 	// Initialize database
 	db, err := repositories.NewPostgresDB(cfg.DatabaseURL)
 	if err != nil {
@@ -64,14 +70,39 @@ func main() {
 	masteryRepo := repositories.NewMasteryRepository(db)
 	weeklyPlanRepo := repositories.NewWeeklyPlanRepository(db)
 	cacheRepo := repositories.NewCacheRepository(redisClient)
+	usageRepo := repositories.NewUsageRepository(db)
+	translationRepo := repositories.NewTranslationRepository(db)
+	lemmaRepo := repositories.NewLemmaRepository(db)
+	lexiconRepo := repositories.NewLexiconRepository(db)
+	importRepo := repositories.NewImportRepository(db)
+	reviewSessionRepo := repositories.NewReviewSessionRepository(db)
 
 	// Initialize services
 	authService := services.NewAuthService(firebaseApp, userRepo)
-	geminiService := services.NewGeminiService(cfg.GeminiAPIKey, logger)
+	usageService := services.NewUsageService(usageRepo, location, logger)
+	promptGuard := services.NewPromptGuard(logger)
+	geminiService := services.NewGeminiService(cfg.GeminiAPIKey, cfg.Gemini, usageService, promptGuard, logger)
 	profileService := services.NewProfileService(profileRepo, userRepo)
-	contentService := services.NewContentService(contentRepo, cacheRepo, geminiService, logger)
-	quizService := services.NewQuizService(quizRepo, masteryRepo, logger)
+	lexiconService := services.NewLexiconService(lexiconRepo, logger)
+	contentService := services.NewContentService(contentRepo, translationRepo, lemmaRepo, masteryRepo, cacheRepo, lexiconService, geminiService, promptGuard, location, logger)
+	quizService := services.NewQuizService(quizRepo, masteryRepo, contentRepo, cacheRepo, geminiService, cfg.Grading, logger)
 	weeklyPlanService := services.NewWeeklyPlanService(weeklyPlanRepo, masteryRepo, geminiService, logger)
+	importService := services.NewImportService(importRepo, contentRepo, lemmaRepo, masteryRepo, promptGuard, logger)
+	exportService := services.NewExportService(contentRepo, logger)
+	reviewService := services.NewReviewSessionService(reviewSessionRepo, contentRepo, quizService, geminiService, cfg.Quiz, location, logger)
+
+	// Imports run in-process, so any left unfinished by a previous run are dead
+	if interrupted, err := importRepo.FailInterrupted(context.Background()); err != nil {
+		logger.Warn("Failed to clean up interrupted imports", zap.Error(err))
+	} else if interrupted > 0 {
+		logger.Info("Marked interrupted imports as failed", zap.Int("count", interrupted))
+	}
+
+	if normalized, err := contentService.NormalizeLemmas(context.Background()); err != nil {
+		logger.Warn("Failed to normalize lemmas", zap.Error(err))
+	} else if normalized > 0 {
+		logger.Info("Stripped articles from lemmas", zap.Int("count", normalized))
+	}
 
 	// Initialize handlers
 	h := handlers.New(
@@ -80,6 +111,11 @@ func main() {
 		contentService,
 		quizService,
 		weeklyPlanService,
+		usageService,
+		lexiconService,
+		importService,
+		exportService,
+		reviewService,
 		logger,
 	)
 
@@ -97,7 +133,7 @@ func main() {
 	router.Use(gzip.Gzip(gzip.DefaultCompression))
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"*"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
@@ -116,13 +152,52 @@ func main() {
 
 		// Content routes
 		v1.POST("/daily-content", middleware.AuthRequired(authService), h.GetDailyContent)
+		v1.POST("/daily-content/skip", middleware.AuthRequired(authService), h.SkipDailyContent)
+		v1.POST("/words", middleware.AuthRequired(authService), h.AddWord)
+		v1.POST("/imports", middleware.AuthRequired(authService), h.CreateImport)
+		v1.GET("/imports/:id", middleware.AuthRequired(authService), h.GetImport)
+		v1.GET("/content", middleware.AuthRequired(authService), h.ListContent)
+		v1.GET("/content/search", middleware.AuthRequired(authService), h.SearchContent)
+		v1.GET("/content/:id", middleware.AuthRequired(authService), h.GetContent)
+		v1.PATCH("/content/:id", middleware.AuthRequired(authService), h.UpdateContent)
+		v1.PUT("/content/:id/bookmark", middleware.AuthRequired(authService), h.BookmarkContent)
+		v1.DELETE("/content/:id/bookmark", middleware.AuthRequired(authService), h.BookmarkContent)
+		v1.POST("/content/:id/tags", middleware.AuthRequired(authService), h.AddContentTags)
+		v1.DELETE("/content/:id/tags/:tag", middleware.AuthRequired(authService), h.RemoveContentTag)
+		v1.GET("/tags", middleware.AuthRequired(authService), h.ListTags)
+		v1.GET("/reviews/due", middleware.AuthRequired(authService), h.ListDueReviews)
+		v1.GET("/export/vocabulary", middleware.AuthRequired(authService), h.ExportVocabulary)
 		v1.POST("/translate", middleware.AuthRequired(authService), h.Translate)
+		v1.GET("/translations", middleware.AuthRequired(authService), h.ListTranslations)
+		v1.POST("/translations/:id/promote", middleware.AuthRequired(authService), h.PromoteTranslation)
 
 		// Quiz routes
 		v1.POST("/quiz/submit", middleware.AuthRequired(authService), h.SubmitQuiz)
+		v1.POST("/review-sessions", middleware.AuthRequired(authService), h.StartReviewSession)
+		v1.GET("/review-sessions/active", middleware.AuthRequired(authService), h.GetActiveReviewSession)
+		v1.GET("/review-sessions/:id", middleware.AuthRequired(authService), h.GetReviewSession)
+		v1.GET("/review-sessions/:id/question", middleware.AuthRequired(authService), h.GetReviewQuestion)
+		v1.POST("/review-sessions/:id/answer", middleware.AuthRequired(authService), h.SubmitReviewAnswer)
+		v1.POST("/review-sessions/:id/finish", middleware.AuthRequired(authService), h.FinishReviewSession)
 
 		// Weekly plan routes
 		v1.POST("/weekly-plan/generate", middleware.AuthRequired(authService), h.GenerateWeeklyPlan)
+
+		// Admin routes
+		admin := v1.Group("/admin", middleware.AuthRequired(authService), middleware.AdminRequired(cfg.AdminEmails))
+		admin.GET("/usage", h.GetUsageSummary)
+		admin.PUT("/usage/budgets", h.SetUsageBudget)
+		admin.POST("/lexicon", h.CreateLexiconEntries)
+		admin.DELETE("/lexicon/:id", h.DisableLexiconEntry)
+	}
+
+	// Background workers
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	if cfg.Pregeneration.Enabled {
+		pregenerationWorker := workers.NewPregenerationWorker(profileRepo, contentService, cfg.Pregeneration, location, logger)
+		go pregenerationWorker.Run(workerCtx)
 	}
 
 	// Start server
@@ -146,6 +221,7 @@ func main() {
 	<-quit
 
 	logger.Info("Shutting down server...")
+	stopWorkers()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS update_usage_budgets_updated_at ON usage_budgets;
+
+DROP INDEX IF EXISTS idx_llm_usage_created_at;
+DROP INDEX IF EXISTS idx_llm_usage_user_created_at;
+
+DROP TABLE IF EXISTS usage_budgets;
+DROP TABLE IF EXISTS llm_usage;
+
+ALTER TABLE users DROP COLUMN IF EXISTS tier;
@@ -0,0 +1,36 @@
+-- Subscription tier used to pick a default LLM budget
+ALTER TABLE users ADD COLUMN tier VARCHAR(20) NOT NULL DEFAULT 'free';
+
+-- LLM usage ledger, one row per Gemini call
+CREATE TABLE llm_usage (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    feature VARCHAR(50) NOT NULL,
+    model VARCHAR(100) NOT NULL,
+    prompt_tokens INTEGER NOT NULL DEFAULT 0,
+    candidates_tokens INTEGER NOT NULL DEFAULT 0,
+    total_tokens INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Token budgets, either for a whole tier or overriding a single user
+CREATE TABLE usage_budgets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tier VARCHAR(20) UNIQUE,
+    user_id UUID UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    daily_token_limit INTEGER NOT NULL CHECK (daily_token_limit >= 0),
+    monthly_token_limit INTEGER NOT NULL CHECK (monthly_token_limit >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT check_budget_scope CHECK ((tier IS NULL) <> (user_id IS NULL))
+);
+
+INSERT INTO usage_budgets (tier, daily_token_limit, monthly_token_limit) VALUES
+    ('free', 20000, 300000),
+    ('pro', 200000, 3000000);
+
+CREATE INDEX idx_llm_usage_user_created_at ON llm_usage(user_id, created_at);
+CREATE INDEX idx_llm_usage_created_at ON llm_usage(created_at);
+
+CREATE TRIGGER update_usage_budgets_updated_at BEFORE UPDATE ON usage_budgets FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
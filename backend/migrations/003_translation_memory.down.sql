@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_translation_memory_user_last_lookup;
+DROP TABLE IF EXISTS translation_memory;
+
+DELETE FROM daily_content WHERE source <> 'daily';
+DROP INDEX IF EXISTS idx_daily_content_user_date_daily;
+ALTER TABLE daily_content ADD CONSTRAINT daily_content_user_id_date_key UNIQUE (user_id, date);
+ALTER TABLE daily_content DROP COLUMN IF EXISTS source;
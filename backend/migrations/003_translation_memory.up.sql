@@ -0,0 +1,24 @@
+-- Content can now come from places other than the daily word, so only the
+-- generated daily word is unique per user and date
+ALTER TABLE daily_content ADD COLUMN source VARCHAR(20) NOT NULL DEFAULT 'daily';
+ALTER TABLE daily_content DROP CONSTRAINT daily_content_user_id_date_key;
+CREATE UNIQUE INDEX idx_daily_content_user_date_daily ON daily_content(user_id, date) WHERE source = 'daily';
+
+-- Translation memory, one row per distinct text a user has looked up
+CREATE TABLE translation_memory (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source_text TEXT NOT NULL,
+    normalized_text TEXT NOT NULL,
+    base_lang VARCHAR(10) NOT NULL,
+    target_lang VARCHAR(10) NOT NULL,
+    translation TEXT NOT NULL,
+    lookup_count INTEGER NOT NULL DEFAULT 1,
+    content_id UUID REFERENCES daily_content(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    last_looked_up_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    UNIQUE(user_id, base_lang, target_lang, normalized_text)
+);
+
+CREATE INDEX idx_translation_memory_user_last_lookup ON translation_memory(user_id, last_looked_up_at DESC);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_user_lemmas_user_language_seen;
+DROP TABLE IF EXISTS user_lemmas;
@@ -0,0 +1,38 @@
+-- Normalized lemmas each user has already been taught, per profile language
+CREATE TABLE user_lemmas (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    language VARCHAR(100) NOT NULL,
+    lemma VARCHAR(255) NOT NULL,
+    content_id UUID REFERENCES daily_content(id) ON DELETE SET NULL,
+    first_seen_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    last_seen_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    UNIQUE(user_id, language, lemma)
+);
+
+CREATE INDEX idx_user_lemmas_user_language_seen ON user_lemmas(user_id, language, last_seen_at DESC);
+
+-- Backfill from existing content, keyed like normalizeLemma and
+-- vocabularyScope: NFC, lower case and single spaces, under the target
+-- language or industry sector. Leading articles are stripped by the server
+-- on startup, so the article lists live only in the application.
+INSERT INTO user_lemmas (user_id, language, lemma, content_id, first_seen_at, last_seen_at)
+SELECT DISTINCT ON (user_id, language, lemma) user_id, language, lemma, content_id, created_at, created_at
+FROM (
+    SELECT
+        dc.user_id,
+        CASE
+            WHEN p.goal_type = 'language' AND p.target_lang IS NOT NULL THEN LOWER(TRIM(p.target_lang))
+            WHEN p.industry_sector IS NOT NULL THEN 'industry:' || LOWER(TRIM(p.industry_sector))
+            ELSE 'industry'
+        END AS language,
+        BTRIM(REGEXP_REPLACE(LOWER(NORMALIZE(dc.word, NFC)), '\s+', ' ', 'g')) AS lemma,
+        dc.id AS content_id,
+        dc.created_at
+    FROM daily_content dc
+    JOIN profiles p ON p.user_id = dc.user_id
+) existing
+WHERE lemma <> ''
+ORDER BY user_id, language, lemma, created_at
+ON CONFLICT DO NOTHING;
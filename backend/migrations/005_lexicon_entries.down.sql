@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS update_lexicon_entries_updated_at ON lexicon_entries;
+
+ALTER TABLE daily_content DROP COLUMN IF EXISTS lexicon_entry_id;
+
+DROP INDEX IF EXISTS idx_lexicon_entries_key;
+DROP TABLE IF EXISTS lexicon_entries;
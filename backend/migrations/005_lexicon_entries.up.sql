@@ -0,0 +1,27 @@
+-- Global word bank shared across users. Language entries are keyed by
+-- language pair and level, industry entries by sector and level; unused key
+-- columns are empty strings so the key can be a plain unique constraint.
+CREATE TABLE lexicon_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    target_lang VARCHAR(10) NOT NULL DEFAULT '',
+    base_lang VARCHAR(10) NOT NULL DEFAULT '',
+    level language_level NOT NULL,
+    sector VARCHAR(100) NOT NULL DEFAULT '',
+    lemma VARCHAR(255) NOT NULL,
+    word VARCHAR(255) NOT NULL,
+    meaning TEXT NOT NULL,
+    examples_target TEXT[] NOT NULL DEFAULT '{}',
+    examples_base TEXT[] DEFAULT '{}',
+    origin VARCHAR(20) NOT NULL DEFAULT 'generated',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    UNIQUE(target_lang, base_lang, level, sector, lemma)
+);
+
+CREATE INDEX idx_lexicon_entries_key ON lexicon_entries(target_lang, base_lang, level, sector) WHERE active;
+
+ALTER TABLE daily_content ADD COLUMN lexicon_entry_id UUID REFERENCES lexicon_entries(id) ON DELETE SET NULL;
+
+CREATE TRIGGER update_lexicon_entries_updated_at BEFORE UPDATE ON lexicon_entries FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_daily_content_created_at;
+
+ALTER TABLE profiles DROP COLUMN IF EXISTS timezone;
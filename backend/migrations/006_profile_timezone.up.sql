@@ -0,0 +1,4 @@
+-- IANA timezone used to find the user's local midnight; NULL means the server default
+ALTER TABLE profiles ADD COLUMN timezone VARCHAR(64);
+
+CREATE INDEX idx_daily_content_created_at ON daily_content(created_at);
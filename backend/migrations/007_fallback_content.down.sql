@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_daily_content_user_date_fallback;
@@ -0,0 +1,3 @@
+-- At most one fallback word per user and date, so repeated requests while
+-- Gemini is down return the same word
+CREATE UNIQUE INDEX idx_daily_content_user_date_fallback ON daily_content(user_id, date) WHERE source = 'fallback';
@@ -0,0 +1,7 @@
+-- Only the first word of each day fits the one-word-per-day index
+DELETE FROM daily_content WHERE source = 'daily' AND position > 0;
+DROP INDEX IF EXISTS idx_daily_content_user_date_daily;
+CREATE UNIQUE INDEX idx_daily_content_user_date_daily ON daily_content(user_id, date) WHERE source = 'daily';
+ALTER TABLE daily_content DROP COLUMN IF EXISTS position;
+
+ALTER TABLE profiles DROP COLUMN IF EXISTS daily_word_count;
@@ -0,0 +1,7 @@
+-- Users can study several words per day; position orders a day's set
+ALTER TABLE profiles ADD COLUMN daily_word_count SMALLINT NOT NULL DEFAULT 1
+    CHECK (daily_word_count BETWEEN 1 AND 10);
+
+ALTER TABLE daily_content ADD COLUMN position SMALLINT NOT NULL DEFAULT 0;
+DROP INDEX IF EXISTS idx_daily_content_user_date_daily;
+CREATE UNIQUE INDEX idx_daily_content_user_date_daily ON daily_content(user_id, date, position) WHERE source = 'daily';
@@ -0,0 +1,13 @@
+ALTER TABLE lexicon_entries
+    DROP COLUMN IF EXISTS part_of_speech,
+    DROP COLUMN IF EXISTS pronunciation,
+    DROP COLUMN IF EXISTS romanization,
+    DROP COLUMN IF EXISTS gender,
+    DROP COLUMN IF EXISTS inflections;
+
+ALTER TABLE daily_content
+    DROP COLUMN IF EXISTS part_of_speech,
+    DROP COLUMN IF EXISTS pronunciation,
+    DROP COLUMN IF EXISTS romanization,
+    DROP COLUMN IF EXISTS gender,
+    DROP COLUMN IF EXISTS inflections;
@@ -0,0 +1,15 @@
+-- Optional structured lexical data, on both study items and the word bank so
+-- words drawn from the bank keep it
+ALTER TABLE daily_content
+    ADD COLUMN part_of_speech VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN pronunciation VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN romanization VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN gender VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN inflections JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE lexicon_entries
+    ADD COLUMN part_of_speech VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN pronunciation VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN romanization VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN gender VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN inflections JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,11 @@
+ALTER TABLE lexicon_entries DROP COLUMN IF EXISTS inappropriate_reports;
+
+-- Skipped words share a position with their replacement, so they can't be kept
+DELETE FROM daily_content WHERE skipped_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_daily_content_user_skipped;
+DROP INDEX IF EXISTS idx_daily_content_user_date_daily;
+CREATE UNIQUE INDEX idx_daily_content_user_date_daily ON daily_content(user_id, date, position) WHERE source = 'daily';
+
+ALTER TABLE daily_content
+    DROP COLUMN IF EXISTS skip_reason,
+    DROP COLUMN IF EXISTS skipped_at;
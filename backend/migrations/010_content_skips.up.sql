@@ -0,0 +1,12 @@
+-- Users can skip a daily word; skipped rows are kept for feedback but no
+-- longer hold their position in the day's set
+ALTER TABLE daily_content
+    ADD COLUMN skip_reason VARCHAR(20),
+    ADD COLUMN skipped_at TIMESTAMP WITH TIME ZONE;
+
+DROP INDEX IF EXISTS idx_daily_content_user_date_daily;
+CREATE UNIQUE INDEX idx_daily_content_user_date_daily ON daily_content(user_id, date, position) WHERE source = 'daily' AND skipped_at IS NULL;
+CREATE INDEX idx_daily_content_user_skipped ON daily_content(user_id, skipped_at DESC) WHERE skipped_at IS NOT NULL;
+
+-- Generated word bank entries are disabled after enough inappropriate reports
+ALTER TABLE lexicon_entries ADD COLUMN inappropriate_reports INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS import_jobs;
+
+ALTER TABLE daily_content DROP COLUMN IF EXISTS tags;
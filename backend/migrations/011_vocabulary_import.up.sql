@@ -0,0 +1,19 @@
+-- Free-form labels on study items, set by imports and by users
+ALTER TABLE daily_content ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+-- Asynchronous CSV/TSV vocabulary imports
+CREATE TABLE import_jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    filename VARCHAR(255) NOT NULL DEFAULT '',
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    imported INTEGER NOT NULL DEFAULT 0,
+    skipped INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    errors JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_import_jobs_user_created ON import_jobs(user_id, created_at DESC);
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_daily_content_meaning_trgm;
+DROP INDEX IF EXISTS idx_daily_content_word_trgm;
+DROP INDEX IF EXISTS idx_daily_content_search;
+
+DROP TRIGGER IF EXISTS daily_content_search_vector ON daily_content;
+DROP FUNCTION IF EXISTS daily_content_search_vector();
+DROP FUNCTION IF EXISTS search_config_for(TEXT);
+
+ALTER TABLE daily_content DROP COLUMN IF EXISTS search_vector;
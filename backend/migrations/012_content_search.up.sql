@@ -0,0 +1,67 @@
+-- Full-text search over a user's vocabulary, with trigram matching as a
+-- fallback for typos
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Maps a language code to its text search configuration. Languages without
+-- a built-in configuration are indexed without stemming.
+CREATE OR REPLACE FUNCTION search_config_for(lang TEXT) RETURNS regconfig AS $$
+    SELECT (CASE lower(split_part(COALESCE(lang, ''), '-', 1))
+        WHEN 'ar' THEN 'arabic'
+        WHEN 'ca' THEN 'catalan'
+        WHEN 'da' THEN 'danish'
+        WHEN 'de' THEN 'german'
+        WHEN 'el' THEN 'greek'
+        WHEN 'en' THEN 'english'
+        WHEN 'es' THEN 'spanish'
+        WHEN 'fi' THEN 'finnish'
+        WHEN 'fr' THEN 'french'
+        WHEN 'hi' THEN 'hindi'
+        WHEN 'hu' THEN 'hungarian'
+        WHEN 'id' THEN 'indonesian'
+        WHEN 'it' THEN 'italian'
+        WHEN 'nl' THEN 'dutch'
+        WHEN 'no' THEN 'norwegian'
+        WHEN 'pt' THEN 'portuguese'
+        WHEN 'ro' THEN 'romanian'
+        WHEN 'ru' THEN 'russian'
+        WHEN 'sv' THEN 'swedish'
+        WHEN 'tr' THEN 'turkish'
+        ELSE 'simple'
+    END)::regconfig
+$$ LANGUAGE sql IMMUTABLE;
+
+-- The word and target examples are in the learned language, the meaning and
+-- base examples in the user's own. Weights rank word matches highest.
+ALTER TABLE daily_content ADD COLUMN search_vector tsvector;
+
+CREATE OR REPLACE FUNCTION daily_content_search_vector() RETURNS trigger AS $$
+DECLARE
+    target_config regconfig := 'english';
+    base_config regconfig := 'english';
+BEGIN
+    SELECT
+        CASE WHEN p.goal_type = 'language' THEN search_config_for(p.target_lang) ELSE 'english'::regconfig END,
+        CASE WHEN p.base_lang IS NOT NULL THEN search_config_for(p.base_lang) ELSE 'english'::regconfig END
+    INTO target_config, base_config
+    FROM profiles p
+    WHERE p.user_id = NEW.user_id;
+
+    NEW.search_vector :=
+        setweight(to_tsvector(COALESCE(target_config, 'english'), NEW.word), 'A') ||
+        setweight(to_tsvector(COALESCE(base_config, 'english'), NEW.meaning), 'B') ||
+        setweight(to_tsvector(COALESCE(target_config, 'english'), array_to_string(NEW.examples_target, ' ')), 'C') ||
+        setweight(to_tsvector(COALESCE(base_config, 'english'), array_to_string(COALESCE(NEW.examples_base, '{}'), ' ')), 'D');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER daily_content_search_vector
+    BEFORE INSERT OR UPDATE OF word, meaning, examples_target, examples_base ON daily_content
+    FOR EACH ROW EXECUTE FUNCTION daily_content_search_vector();
+
+-- Backfill existing rows through the trigger
+UPDATE daily_content SET word = word;
+
+CREATE INDEX idx_daily_content_search ON daily_content USING GIN (search_vector);
+CREATE INDEX idx_daily_content_word_trgm ON daily_content USING GIN (word gin_trgm_ops);
+CREATE INDEX idx_daily_content_meaning_trgm ON daily_content USING GIN (meaning gin_trgm_ops);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_daily_content_tags;
+DROP INDEX IF EXISTS idx_daily_content_user_bookmarked;
+
+ALTER TABLE daily_content
+    DROP COLUMN IF EXISTS note,
+    DROP COLUMN IF EXISTS bookmarked;
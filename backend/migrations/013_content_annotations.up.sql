@@ -0,0 +1,8 @@
+-- Users can bookmark content and keep a personal note on it; tags, added by
+-- imports so far, become editable too
+ALTER TABLE daily_content
+    ADD COLUMN bookmarked BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN note TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_daily_content_user_bookmarked ON daily_content(user_id, date DESC) WHERE bookmarked;
+CREATE INDEX idx_daily_content_tags ON daily_content USING GIN (tags);
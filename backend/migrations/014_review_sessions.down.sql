@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS review_session_items;
+DROP TABLE IF EXISTS review_sessions;
@@ -0,0 +1,36 @@
+-- Review sessions run several quiz questions over due and weak words. They
+-- are stored so a session can be resumed after the app restarts.
+CREATE TABLE review_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+-- A user has at most one session in progress
+CREATE UNIQUE INDEX idx_review_sessions_user_active ON review_sessions(user_id) WHERE status = 'active';
+CREATE INDEX idx_review_sessions_user_created ON review_sessions(user_id, created_at DESC);
+
+-- Questions are generated when first asked and kept, so asking again
+-- returns the same question
+CREATE TABLE review_session_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    session_id UUID NOT NULL REFERENCES review_sessions(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    content_id UUID NOT NULL REFERENCES daily_content(id) ON DELETE CASCADE,
+    quiz_type quiz_type NOT NULL,
+    question TEXT,
+    options TEXT[] NOT NULL DEFAULT '{}',
+    correct_answer TEXT,
+    user_answer TEXT,
+    is_correct BOOLEAN,
+    quiz_log_id UUID REFERENCES quiz_logs(id) ON DELETE SET NULL,
+    mastery_before INTEGER,
+    mastery_after INTEGER,
+    next_review_date DATE,
+    answered_at TIMESTAMP WITH TIME ZONE,
+
+    UNIQUE(session_id, position)
+);
@@ -0,0 +1,7 @@
+ALTER TABLE review_session_items
+    DROP COLUMN IF EXISTS feedback,
+    DROP COLUMN IF EXISTS grade;
+
+ALTER TABLE quiz_logs
+    DROP COLUMN IF EXISTS feedback,
+    DROP COLUMN IF EXISTS grade;
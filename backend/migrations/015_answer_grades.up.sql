@@ -0,0 +1,11 @@
+-- Answers are graded exact, close or wrong rather than just right or wrong,
+-- with feedback on close answers
+ALTER TABLE quiz_logs
+    ADD COLUMN grade VARCHAR(10) NOT NULL DEFAULT 'wrong',
+    ADD COLUMN feedback TEXT NOT NULL DEFAULT '';
+
+UPDATE quiz_logs SET grade = 'exact' WHERE is_correct;
+
+ALTER TABLE review_session_items
+    ADD COLUMN grade VARCHAR(10),
+    ADD COLUMN feedback TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+ALTER TABLE review_session_items DROP COLUMN IF EXISTS rubric;
+
+ALTER TABLE quiz_logs DROP COLUMN IF EXISTS rubric;
+
+-- Postgres can't drop an enum value, so 'sentence' stays in quiz_type
@@ -0,0 +1,8 @@
+-- Sentence questions ask the user to write a sentence with the word
+ALTER TYPE quiz_type ADD VALUE IF NOT EXISTS 'sentence';
+
+-- Free-text answers are graded by the LLM against a rubric, which is kept
+-- with the answer
+ALTER TABLE quiz_logs ADD COLUMN rubric JSONB;
+
+ALTER TABLE review_session_items ADD COLUMN rubric JSONB;
@@ -0,0 +1,5 @@
+ALTER TABLE review_session_items DROP COLUMN IF EXISTS terms;
+
+ALTER TABLE quiz_logs DROP COLUMN IF EXISTS terms;
+
+-- Postgres can't drop an enum value, so the new quiz types stay in quiz_type
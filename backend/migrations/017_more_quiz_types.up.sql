@@ -0,0 +1,11 @@
+-- Matching pairs recent words with their meanings, word order rebuilds a
+-- scrambled example sentence and reverse translation turns a base-language
+-- example into the target language
+ALTER TYPE quiz_type ADD VALUE IF NOT EXISTS 'matching';
+ALTER TYPE quiz_type ADD VALUE IF NOT EXISTS 'word_order';
+ALTER TYPE quiz_type ADD VALUE IF NOT EXISTS 'reverse_translation';
+
+-- The words of a matching question, in the order its answer follows
+ALTER TABLE quiz_logs ADD COLUMN terms TEXT[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE review_session_items ADD COLUMN terms TEXT[] NOT NULL DEFAULT '{}';